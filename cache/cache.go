@@ -0,0 +1,239 @@
+// Package cache implements the local cache layout shared with the Hugging Face Python libraries.
+//
+// Files are stored once per content in blobs, named after their ETag, and exposed per commit in snapshots
+// through symlinks. Refs map revisions such as "main" to the commit they last resolved to:
+//
+//	<dir>/models--org--name/blobs/<etag>
+//	<dir>/models--org--name/refs/main
+//	<dir>/models--org--name/snapshots/<commit>/<filename> -> ../../blobs/<etag>
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var commitHashRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// lockPollInterval is the interval at which a lock held by another process is checked.
+const lockPollInterval = 50 * time.Millisecond
+
+// Dir returns the cache directory, honouring the HF_HUB_CACHE and HF_HOME environment variables.
+// It defaults to ~/.cache/huggingface/hub.
+func Dir() string {
+	if dir := os.Getenv("HF_HUB_CACHE"); dir != "" {
+		return dir
+	}
+	// HUGGINGFACE_HUB_CACHE is the deprecated name of HF_HUB_CACHE.
+	if dir := os.Getenv("HUGGINGFACE_HUB_CACHE"); dir != "" {
+		return dir
+	}
+	if home := os.Getenv("HF_HOME"); home != "" {
+		return filepath.Join(home, "hub")
+	}
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "huggingface", "hub")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "huggingface", "hub")
+	}
+	return filepath.Join(home, ".cache", "huggingface", "hub")
+}
+
+// IsCommitHash reports whether a revision is a full commit hash rather than a branch or tag name.
+func IsCommitHash(revision string) bool {
+	return commitHashRegexp.MatchString(revision)
+}
+
+// Cache is a cache directory.
+type Cache struct {
+	dir string
+}
+
+// New creates a cache rooted at dir.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the root directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// RepoDir returns the directory of a repository, e.g. "models--org--name".
+// The repository type is one of "model", "dataset" or "space".
+func (c *Cache) RepoDir(repoType string, repoID string) string {
+	return filepath.Join(c.dir, repoFolderName(repoType, repoID))
+}
+
+// BlobPath returns the path of the blob holding the content identified by etag.
+func (c *Cache) BlobPath(repoType string, repoID string, etag string) string {
+	return filepath.Join(c.RepoDir(repoType, repoID), "blobs", etag)
+}
+
+// SnapshotPath returns the path of a file in the snapshot of a commit.
+func (c *Cache) SnapshotPath(repoType string, repoID string, commit string, filename string) string {
+	return filepath.Join(c.SnapshotDir(repoType, repoID, commit), filepath.FromSlash(filename))
+}
+
+// SnapshotDir returns the directory of the snapshot of a commit.
+func (c *Cache) SnapshotDir(repoType string, repoID string, commit string) string {
+	return filepath.Join(c.RepoDir(repoType, repoID), "snapshots", commit)
+}
+
+// ReadRef returns the commit a revision last resolved to. Commit hashes resolve to themselves.
+func (c *Cache) ReadRef(repoType string, repoID string, revision string) (string, error) {
+	if IsCommitHash(revision) {
+		return revision, nil
+	}
+	data, err := os.ReadFile(c.refPath(repoType, repoID, revision))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteRef records the commit a revision resolved to.
+func (c *Cache) WriteRef(repoType string, repoID string, revision string, commit string) error {
+	if revision == commit {
+		return nil
+	}
+	current, err := c.ReadRef(repoType, repoID, revision)
+	if err == nil && current == commit {
+		return nil
+	}
+	return WriteFileAtomic(c.refPath(repoType, repoID, revision), []byte(commit), 0o644)
+}
+
+func (c *Cache) refPath(repoType string, repoID string, revision string) string {
+	return filepath.Join(c.RepoDir(repoType, repoID), "refs", filepath.FromSlash(revision))
+}
+
+// Lookup returns the path of a cached file at a revision and the commit the revision resolved to,
+// without any network access. It reports false if the file is not cached.
+func (c *Cache) Lookup(repoType string, repoID string, revision string, filename string) (string, string, bool) {
+	commit, err := c.ReadRef(repoType, repoID, revision)
+	if err != nil {
+		return "", "", false
+	}
+	p := c.SnapshotPath(repoType, repoID, commit, filename)
+	if _, err := os.Stat(p); err != nil {
+		return "", "", false
+	}
+	return p, commit, true
+}
+
+// LinkSnapshot exposes the blob identified by etag as a file of the snapshot of a commit, and returns the path
+// of the file in the snapshot. The file is a relative symlink to the blob, or a copy of it where symlinks are
+// not supported.
+func (c *Cache) LinkSnapshot(repoType string, repoID string, commit string, filename string, etag string) (string, error) {
+	blob := c.BlobPath(repoType, repoID, etag)
+	p := c.SnapshotPath(repoType, repoID, commit, filename)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+	target, err := filepath.Rel(filepath.Dir(p), blob)
+	if err != nil {
+		return "", err
+	}
+	if current, err := os.Readlink(p); err == nil && current == target {
+		return p, nil
+	}
+
+	// The link is created under a temporary name and renamed, so that it is replaced atomically.
+	tmp := p + ".tmp-link"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		if err := copyFile(blob, tmp); err != nil {
+			return "", err
+		}
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return p, nil
+}
+
+// Lock acquires an exclusive lock shared by every process using the cache, and returns the function releasing
+// it. Locks are identified by a name within a repository, typically the ETag of the blob being written.
+// Waiting for a lock held by another process stops with the error of ctx once it is done.
+func (c *Cache) Lock(ctx context.Context, repoType string, repoID string, name string) (func() error, error) {
+	p := filepath.Join(c.dir, ".locks", repoFolderName(repoType, repoID), name+".lock")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	return lockFile(ctx, p)
+}
+
+// waitLock waits for lockPollInterval before a lock is tried again, or returns the error of ctx if it is done
+// first.
+func waitLock(ctx context.Context) error {
+	timer := time.NewTimer(lockPollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// WriteFileAtomic writes data to a temporary file next to p and renames it to p, so that readers never
+// observe a partially written file.
+func WriteFileAtomic(p string, data []byte, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return errors.Join(err, os.Remove(dst))
+	}
+	return out.Close()
+}
+
+// repoFolderName returns the name of the directory of a repository, e.g. "models--org--name".
+func repoFolderName(repoType string, repoID string) string {
+	if repoType == "" {
+		repoType = "model"
+	}
+	parts := append([]string{repoType + "s"}, strings.Split(repoID, "/")...)
+	return strings.Join(parts, "--")
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDir(t *testing.T) {
+	t.Setenv("HF_HUB_CACHE", "")
+	t.Setenv("HUGGINGFACE_HUB_CACHE", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HF_HOME", "/data/hf")
+	if got := Dir(); got != filepath.Join("/data/hf", "hub") {
+		t.Errorf("Dir() = %s, want /data/hf/hub", got)
+	}
+	t.Setenv("HF_HUB_CACHE", "/data/cache")
+	if got := Dir(); got != "/data/cache" {
+		t.Errorf("Dir() = %s, want /data/cache", got)
+	}
+}
+
+func TestCache_RepoDir(t *testing.T) {
+	c := New("/cache")
+	tests := []struct {
+		repoType string
+		repoID   string
+		want     string
+	}{
+		{repoType: "model", repoID: "org/name", want: "models--org--name"},
+		{repoType: "dataset", repoID: "org/name", want: "datasets--org--name"},
+		{repoType: "space", repoID: "name", want: "spaces--name"},
+		{repoType: "", repoID: "gpt2", want: "models--gpt2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := c.RepoDir(tt.repoType, tt.repoID); got != filepath.Join("/cache", tt.want) {
+				t.Errorf("RepoDir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCache_LinkSnapshot(t *testing.T) {
+	c := New(t.TempDir())
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	if _, _, ok := c.Lookup("model", "org/name", "main", "config.json"); ok {
+		t.Fatalf("Expected cache miss")
+	}
+
+	blob := c.BlobPath("model", "org/name", "etag")
+	if err := WriteFileAtomic(blob, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.WriteRef("model", "org/name", "main", commit); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p, err := c.LinkSnapshot("model", "org/name", commit, "sub/config.json", "etag")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := filepath.Join(c.SnapshotDir("model", "org/name", commit), "sub", "config.json"); p != want {
+		t.Errorf("LinkSnapshot() = %s, want %s", p, want)
+	}
+	if target, err := os.Readlink(p); err == nil && target != filepath.Join("..", "..", "..", "blobs", "etag") {
+		t.Errorf("Unexpected symlink target %s", target)
+	}
+
+	for _, revision := range []string{"main", commit} {
+		got, gotCommit, ok := c.Lookup("model", "org/name", revision, "sub/config.json")
+		if !ok || got != p || gotCommit != commit {
+			t.Errorf("Lookup(%s) = %s, %s, %v", revision, got, gotCommit, ok)
+		}
+	}
+	data, _ := os.ReadFile(p)
+	if string(data) != "{}" {
+		t.Errorf("Unexpected content %q", data)
+	}
+}
+
+func TestCache_Lock(t *testing.T) {
+	c := New(t.TempDir())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	holders, maxHolders := 0, 0
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := c.Lock(context.Background(), "model", "org/name", "etag")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			holders++
+			maxHolders = max(maxHolders, holders)
+			mu.Unlock()
+
+			// The lock is held while other goroutines try to acquire it.
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			_ = unlock()
+		}()
+	}
+	wg.Wait()
+	if maxHolders != 1 {
+		t.Errorf("Expected lock to be exclusive, got %d holders", maxHolders)
+	}
+}
+
+func TestCache_LockBlocks(t *testing.T) {
+	c := New(t.TempDir())
+	unlock, err := c.Lock(context.Background(), "model", "org/name", "etag")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := c.Lock(context.Background(), "model", "org/name", "etag")
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		} else {
+			_ = unlock()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Expected lock to block while it is held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_ = unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected lock to be acquired once released")
+	}
+}
+
+func TestCache_LockCanceled(t *testing.T) {
+	c := New(t.TempDir())
+	unlock, err := c.Lock(context.Background(), "model", "org/name", "etag")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.Lock(ctx, "model", "org/name", "etag"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded error, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !unix
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// lockRefreshInterval is the interval at which the holder of a lock updates the modification time of its file.
+	lockRefreshInterval = 10 * time.Second
+	// lockStaleAge is the age after which a lock file that is no longer refreshed is considered left behind by a
+	// process that crashed, and is removed.
+	lockStaleAge = 6 * lockRefreshInterval
+)
+
+// lockFile acquires an exclusive lock by creating the file at p, polling until other holders remove it or ctx is
+// done. The holder keeps the file fresh until it releases the lock, so that a file left behind by a crashed
+// process is recognized as stale and taken over.
+func lockFile(ctx context.Context, p string) (func() error, error) {
+	for {
+		f, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			f.Close()
+			return holdLockFile(p), nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(p); err == nil && time.Since(info.ModTime()) > lockStaleAge {
+			_ = os.Remove(p)
+			continue
+		}
+		if err := waitLock(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// holdLockFile refreshes the lock file at p until the returned function releases the lock by removing it.
+func holdLockFile(p string) func() error {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				_ = os.Chtimes(p, now, now)
+			}
+		}
+	}()
+	return func() error {
+		close(done)
+		<-stopped
+		return os.Remove(p)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !unix
+
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFile_Stale(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "etag.lock")
+	if err := os.WriteFile(p, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStaleAge)
+	if err := os.Chtimes(p, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := lockFile(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Errorf("Expected lock file to be removed")
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package cache
+
+import (
+	"context"
+	"os"
+	"syscall"
+)
+
+// lockFile acquires an exclusive advisory lock on the file at p, polling until other holders release it or ctx is
+// done.
+func lockFile(ctx context.Context, p string) (func() error, error) {
+	f, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != syscall.EWOULDBLOCK {
+			break
+		}
+		if err = waitLock(ctx); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() error {
+		defer f.Close()
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
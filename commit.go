@@ -0,0 +1,286 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// CommitOperation is an operation of a commit: CommitOperationAdd, CommitOperationDelete or CommitOperationCopy.
+type CommitOperation interface {
+	commitOperation()
+}
+
+// CommitOperationAdd adds a file to a repository, or replaces it. The content is read from Content, or from
+// the local file at LocalPath if Content is nil.
+type CommitOperationAdd struct {
+	PathInRepo string
+	Content    []byte
+	LocalPath  string
+}
+
+// CommitOperationDelete deletes a file from a repository, or a folder and its content if IsFolder is set or
+// the path ends with "/".
+type CommitOperationDelete struct {
+	PathInRepo string
+	IsFolder   bool
+}
+
+// CommitOperationCopy copies a file of a repository, as of SrcRevision or of the revision committed to if empty.
+type CommitOperationCopy struct {
+	SrcPath     string
+	DstPath     string
+	SrcRevision string
+}
+
+func (CommitOperationAdd) commitOperation()    {}
+func (CommitOperationDelete) commitOperation() {}
+func (CommitOperationCopy) commitOperation()   {}
+
+// open returns the content of the file to add along with its size.
+func (op CommitOperationAdd) open() (contentReader, int64, error) {
+	if op.Content != nil || op.LocalPath == "" {
+		return nopCloserReaderAt{bytes.NewReader(op.Content)}, int64(len(op.Content)), nil
+	}
+	f, err := os.Open(op.LocalPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// contentReader is the content of a file to add, which can be read at any offset for uploads in parts.
+type contentReader interface {
+	io.ReaderAt
+	io.Closer
+}
+
+type nopCloserReaderAt struct {
+	io.ReaderAt
+}
+
+func (nopCloserReaderAt) Close() error {
+	return nil
+}
+
+// CommitOptions configures a commit.
+type CommitOptions struct {
+	// Description is the extended description of the commit.
+	Description string
+	// ParentCommit is the commit the revision is expected to point to. The commit fails if the revision moved,
+	// which prevents concurrent changes from being overwritten.
+	ParentCommit string
+	// CreatePR opens a pull request with the commit instead of committing to the revision.
+	CreatePR bool
+	// MaxWorkers is the number of LFS files uploaded concurrently. It defaults to DefaultMaxWorkers.
+	MaxWorkers int
+	// Progress, if set, is called as LFS files are uploaded.
+	Progress UploadProgressFunc
+}
+
+// UploadProgressFunc is called as a file is uploaded with its path in the repository, the number of bytes
+// uploaded so far and its size.
+type UploadProgressFunc func(path string, uploaded int64, total int64)
+
+// CreateCommit commits operations to a revision of a repository in a single commit.
+//
+// Files added are uploaded as the Hub decides: small files are sent inline with the commit, while large files
+// and files tracked by the .gitattributes of the repository are uploaded to LFS storage first. Files ignored by
+// the .gitignore of the repository are skipped.
+func (r *Repository) CreateCommit(ctx context.Context, repoType RepoType, id string, revision string, operations []CommitOperation, message string, opts CommitOptions) (*CommitInfo, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	if message == "" {
+		return nil, errors.New("commit message is required")
+	}
+	uploads, err := r.uploadFiles(ctx, repoType, id, revision, operations, opts)
+	if err != nil {
+		return nil, err
+	}
+	body, err := r.commitPayload(ctx, repoType, id, revision, operations, uploads, message, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare commit: %w", err)
+	}
+
+	rawURL := fmt.Sprintf("%s/%s/%s/commit/%s", r.httpClient.baseURL, repoType.apiPath(), id, url.PathEscape(revision))
+	if opts.CreatePR {
+		rawURL += "?create_pr=1"
+	}
+	req, err := r.httpClient.newURLRequest(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	var info CommitInfo
+	if err := r.httpClient.doRequest(req, &info); err != nil {
+		return nil, fmt.Errorf("failed to create commit: %w", err)
+	}
+	info.PullRequestNumber = pullRequestNumber(info.PullRequestURL)
+	return &info, nil
+}
+
+// commitPayload serializes a commit as NDJSON: a header line followed by one line per operation.
+// Files uploaded to LFS storage are referenced by their sha256.
+func (r *Repository) commitPayload(ctx context.Context, repoType RepoType, id string, revision string, operations []CommitOperation, uploads map[string]*fileUpload, message string, opts CommitOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	line := func(key string, value any) error {
+		return enc.Encode(map[string]any{"key": key, "value": value})
+	}
+
+	header := commitHeader{Summary: message, Description: opts.Description, ParentCommit: opts.ParentCommit}
+	if err := line("header", header); err != nil {
+		return nil, err
+	}
+	for _, operation := range operations {
+		var err error
+		switch op := operation.(type) {
+		case CommitOperationAdd:
+			upload := uploads[op.PathInRepo]
+			switch {
+			case upload == nil || upload.mode == uploadModeRegular:
+				err = r.addFileLine(op, line)
+			case upload.mode == uploadModeLFS:
+				err = line("lfsFile", commitLFSFile{Path: op.PathInRepo, Algo: "sha256", OID: upload.sha256, Size: upload.size})
+			case upload.mode == uploadModeIgnored:
+				// Files ignored by the .gitignore of the repository are not committed.
+			default:
+				err = fmt.Errorf("unsupported upload mode %q for %s", upload.mode, op.PathInRepo)
+			}
+		case CommitOperationDelete:
+			key := "deletedFile"
+			if op.IsFolder || strings.HasSuffix(op.PathInRepo, "/") {
+				key = "deletedFolder"
+			}
+			err = line(key, commitDeletedFile{Path: op.PathInRepo})
+		case CommitOperationCopy:
+			err = r.copyFileLine(ctx, repoType, id, revision, op, line)
+		default:
+			err = fmt.Errorf("unsupported commit operation %T", operation)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// addFileLine serializes a file added with its content encoded in base64.
+func (r *Repository) addFileLine(op CommitOperationAdd, line func(string, any) error) error {
+	content, size, err := op.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	data, err := io.ReadAll(io.NewSectionReader(content, 0, size))
+	if err != nil {
+		return err
+	}
+	return line("file", commitFile{
+		Path:     op.PathInRepo,
+		Content:  base64.StdEncoding.EncodeToString(data),
+		Encoding: "base64",
+	})
+}
+
+// copyFileLine serializes a copied file. LFS files are copied by reference, while the content of other files
+// is downloaded and added again.
+func (r *Repository) copyFileLine(ctx context.Context, repoType RepoType, id string, revision string, op CommitOperationCopy, line func(string, any) error) error {
+	srcRevision := op.SrcRevision
+	if srcRevision == "" {
+		srcRevision = revision
+	}
+	entries, err := r.GetPathsInfo(ctx, repoType, id, srcRevision, []string{op.SrcPath}, false)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 || entries[0].IsDir() {
+		return fmt.Errorf("cannot copy %s: file not found", op.SrcPath)
+	}
+	if lfs := entries[0].LFS; lfs != nil {
+		return line("lfsFile", commitLFSFile{Path: op.DstPath, Algo: "sha256", OID: lfs.SHA256, Size: lfs.Size})
+	}
+
+	var content bytes.Buffer
+	rawURL := r.httpClient.resolveURL(repoType, id, srcRevision, op.SrcPath)
+	if err := r.httpClient.readURL(ctx, rawURL, &content); err != nil {
+		return fmt.Errorf("failed to read %s: %w", op.SrcPath, err)
+	}
+	return r.addFileLine(CommitOperationAdd{PathInRepo: op.DstPath, Content: content.Bytes()}, line)
+}
+
+// readURL copies the content at an absolute URL to w.
+func (c *HttpClient) readURL(ctx context.Context, rawURL string, w io.Writer) error {
+	req, err := c.newURLRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// pullRequestNumber extracts the number of a pull request from its URL, e.g. ".../discussions/12".
+func pullRequestNumber(rawURL string) int {
+	if rawURL == "" {
+		return 0
+	}
+	n, _ := strconv.Atoi(path.Base(rawURL))
+	return n
+}
+
+type commitHeader struct {
+	Summary      string `json:"summary"`
+	Description  string `json:"description,omitempty"`
+	ParentCommit string `json:"parentCommit,omitempty"`
+}
+
+// commitFile is a file added with its content. The content is always sent, even for empty files.
+type commitFile struct {
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+type commitDeletedFile struct {
+	Path string `json:"path"`
+}
+
+type commitLFSFile struct {
+	Path string `json:"path"`
+	Algo string `json:"algo"`
+	OID  string `json:"oid"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// CommitInfo describes a created commit.
+type CommitInfo struct {
+	CommitURL string `json:"commitUrl"`
+	OID       string `json:"commitOid"`
+	// PullRequestURL is the URL of the pull request opened with CommitOptions.CreatePR.
+	PullRequestURL    string `json:"pullRequestUrl,omitempty"`
+	PullRequestNumber int    `json:"-"`
+}
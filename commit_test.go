@@ -0,0 +1,143 @@
+package huggo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepository_CreateCommit(t *testing.T) {
+	var lines []map[string]any
+	var query string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/models/user/model/paths-info/main", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Paths []string `json:"paths"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		entries := map[string]RepoTreeEntry{
+			"model.safetensors": {Type: "file", Path: "model.safetensors", LFS: &LFSInfo{SHA256: "abc", Size: 42}},
+			"config.json":       {Type: "file", Path: "config.json"},
+		}
+		_ = json.NewEncoder(w).Encode([]RepoTreeEntry{entries[payload.Paths[0]]})
+	})
+	mux.HandleFunc("/api/models/user/model/preupload/main", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		files := make([]map[string]any, len(payload.Files))
+		for i, file := range payload.Files {
+			files[i] = map[string]any{"path": file.Path, "uploadMode": "regular", "shouldIgnore": false}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+	})
+	mux.HandleFunc("/user/model/resolve/main/config.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/api/models/user/model/commit/main", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+		query = r.URL.RawQuery
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+			}
+			lines = append(lines, line)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"success":        true,
+			"commitOid":      "def456",
+			"commitUrl":      "https://huggingface.co/user/model/commit/def456",
+			"pullRequestUrl": "https://huggingface.co/user/model/discussions/7",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	localPath := filepath.Join(t.TempDir(), "README.md")
+	_ = os.WriteFile(localPath, []byte("# Model"), 0o644)
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	operations := []CommitOperation{
+		CommitOperationAdd{PathInRepo: "README.md", LocalPath: localPath},
+		CommitOperationAdd{PathInRepo: "data/a.txt", Content: []byte("hello")},
+		CommitOperationAdd{PathInRepo: "pkg/__init__.py", Content: []byte{}},
+		CommitOperationDelete{PathInRepo: "old.txt"},
+		CommitOperationDelete{PathInRepo: "logs/"},
+		CommitOperationCopy{SrcPath: "model.safetensors", DstPath: "backup/model.safetensors"},
+		CommitOperationCopy{SrcPath: "config.json", DstPath: "backup/config.json"},
+	}
+	info, err := repository.CreateCommit(context.Background(), RepoTypeModel, "user/model", "", operations, "Update model", CommitOptions{
+		Description:  "Details",
+		ParentCommit: "abc123",
+		CreatePR:     true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.OID != "def456" || info.PullRequestNumber != 7 {
+		t.Errorf("Unexpected commit info %+v", info)
+	}
+	if query != "create_pr=1" {
+		t.Errorf("Unexpected query %q", query)
+	}
+
+	want := []struct {
+		key   string
+		value map[string]any
+	}{
+		{"header", map[string]any{"summary": "Update model", "description": "Details", "parentCommit": "abc123"}},
+		{"file", map[string]any{"path": "README.md", "content": "IyBNb2RlbA==", "encoding": "base64"}},
+		{"file", map[string]any{"path": "data/a.txt", "content": "aGVsbG8=", "encoding": "base64"}},
+		{"file", map[string]any{"path": "pkg/__init__.py", "content": "", "encoding": "base64"}},
+		{"deletedFile", map[string]any{"path": "old.txt"}},
+		{"deletedFolder", map[string]any{"path": "logs/"}},
+		{"lfsFile", map[string]any{"path": "backup/model.safetensors", "algo": "sha256", "oid": "abc", "size": float64(42)}},
+		{"file", map[string]any{"path": "backup/config.json", "content": "e30=", "encoding": "base64"}},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d", len(want), len(lines))
+	}
+	for i, w := range want {
+		value, _ := lines[i]["value"].(map[string]any)
+		if lines[i]["key"] != w.key || len(value) != len(w.value) {
+			t.Errorf("Unexpected line %d: %v", i, lines[i])
+			continue
+		}
+		for k, v := range w.value {
+			if value[k] != v {
+				t.Errorf("Unexpected %s of line %d: %v", k, i, value[k])
+			}
+		}
+	}
+}
+
+func TestRepository_CreateCommit_ParentCommitMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		_, _ = w.Write([]byte(`{"error":"A commit has happened since"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	operations := []CommitOperation{CommitOperationDelete{PathInRepo: "old.txt"}}
+	_, err := repository.CreateCommit(context.Background(), RepoTypeDataset, "user/dataset", "main", operations, "Delete", CommitOptions{ParentCommit: "abc123"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected precondition failure, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package huggo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Croissant types as found in the "@type" and "dataType" fields of Croissant metadata.
+const (
+	CroissantTypeFileObject = "cr:FileObject"
+	CroissantTypeFileSet    = "cr:FileSet"
+	CroissantTypeRecordSet  = "cr:RecordSet"
+	CroissantTypeField      = "cr:Field"
+	CroissantTypeSplit      = "cr:Split"
+)
+
+// GetDatasetCroissant fetches the Croissant (ML Commons JSON-LD) metadata of a dataset.
+func (s *Search) GetDatasetCroissant(id string) (*Croissant, error) {
+	var croissant Croissant
+	path := fmt.Sprintf("/datasets/%s/croissant", id)
+	err := s.httpClient.Get(path, &croissant)
+	if err != nil {
+		return nil, err
+	}
+	return &croissant, nil
+}
+
+// Croissant is the Croissant metadata of a dataset.
+// Its distributions refer to the parquet files of the dataset on the ParquetRevision.
+type Croissant struct {
+	Context       json.RawMessage         `json:"@context,omitempty"`
+	Type          string                  `json:"@type"`
+	Name          string                  `json:"name"`
+	AlternateName CroissantValues         `json:"alternateName,omitempty"`
+	Description   string                  `json:"description"`
+	ConformsTo    string                  `json:"conformsTo"`
+	CiteAs        string                  `json:"citeAs,omitempty"`
+	License       CroissantValues         `json:"license,omitempty"`
+	URL           string                  `json:"url"`
+	Keywords      CroissantValues         `json:"keywords,omitempty"`
+	Distribution  []CroissantDistribution `json:"distribution"`
+	RecordSet     []CroissantRecord       `json:"recordSet"`
+}
+
+// CroissantDistribution is a file object, such as the repository, or a set of files of the dataset.
+type CroissantDistribution struct {
+	Type           string          `json:"@type"`
+	ID             string          `json:"@id"`
+	Name           string          `json:"name,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	ContentURL     string          `json:"contentUrl,omitempty"`
+	EncodingFormat string          `json:"encodingFormat,omitempty"`
+	SHA256         string          `json:"sha256,omitempty"`
+	ContainedIn    *CroissantRef   `json:"containedIn,omitempty"`
+	Includes       CroissantValues `json:"includes,omitempty"`
+}
+
+// CroissantRecord is a record set: the records of a dataset config, or the names of its splits.
+type CroissantRecord struct {
+	Type        string           `json:"@type"`
+	ID          string           `json:"@id"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	DataType    CroissantValues  `json:"dataType,omitempty"`
+	Key         *CroissantRef    `json:"key,omitempty"`
+	Field       []CroissantField `json:"field"`
+	// Data holds the records of record sets whose records are inlined, such as splits.
+	Data []map[string]any `json:"data,omitempty"`
+}
+
+type CroissantField struct {
+	Type        string               `json:"@type"`
+	ID          string               `json:"@id"`
+	Name        string               `json:"name,omitempty"`
+	Description string               `json:"description,omitempty"`
+	DataType    CroissantValues      `json:"dataType,omitempty"`
+	Repeated    bool                 `json:"repeated,omitempty"`
+	Source      *CroissantSource     `json:"source,omitempty"`
+	References  *CroissantReferences `json:"references,omitempty"`
+	SubField    []CroissantField     `json:"subField,omitempty"`
+}
+
+type CroissantSource struct {
+	FileSet    *CroissantRef `json:"fileSet,omitempty"`
+	FileObject *CroissantRef `json:"fileObject,omitempty"`
+	Extract    struct {
+		Column       string `json:"column,omitempty"`
+		FileProperty string `json:"fileProperty,omitempty"`
+		JSONPath     string `json:"jsonPath,omitempty"`
+	} `json:"extract"`
+	Transform *struct {
+		Regex    string `json:"regex,omitempty"`
+		JSONPath string `json:"jsonPath,omitempty"`
+	} `json:"transform,omitempty"`
+}
+
+type CroissantReferences struct {
+	Field CroissantRef `json:"field"`
+}
+
+// CroissantRef references another node of the metadata by its ID.
+type CroissantRef struct {
+	ID string `json:"@id"`
+}
+
+// CroissantValues holds a property that is either a single value or a list of values.
+type CroissantValues []string
+
+func (v *CroissantValues) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = CroissantValues{value}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("unsupported type for CroissantValues: %s", string(data))
+	}
+	*v = values
+	return nil
+}
+
+func (v CroissantValues) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+// Splits returns the names of the splits of a dataset config, as listed by its splits record set.
+func (c *Croissant) Splits(config string) []string {
+	for _, record := range c.RecordSet {
+		if !slices.Contains(record.DataType, CroissantTypeSplit) || record.Key == nil {
+			continue
+		}
+		if record.ID != config+"_splits" && record.Name != config+"_splits" {
+			continue
+		}
+		var splits []string
+		for _, data := range record.Data {
+			if split, ok := data[record.Key.ID].(string); ok {
+				splits = append(splits, split)
+			}
+		}
+		return splits
+	}
+	return nil
+}
+
+// CardData converts the metadata into the shape of the dataset card data, with one config per record set.
+// The data files of the configs point to the parquet files on the ParquetRevision.
+func (c *Croissant) CardData() DatasetCardData {
+	cardData := DatasetCardData{
+		Tags: c.Keywords,
+	}
+	if c.Name != "" {
+		name := c.Name
+		cardData.PrettyName = &name
+	}
+	if len(c.License) > 0 {
+		// Licenses are given as URLs such as https://choosealicense.com/licenses/mit/.
+		cardData.License = path.Base(strings.TrimSuffix(c.License[0], "/"))
+	}
+
+	fileSets := make(map[string]CroissantDistribution)
+	for _, distribution := range c.Distribution {
+		if distribution.Type == CroissantTypeFileSet {
+			fileSets[distribution.ID] = distribution
+		}
+	}
+	for _, record := range c.RecordSet {
+		if slices.Contains(record.DataType, CroissantTypeSplit) {
+			continue
+		}
+		config := Config{ConfigName: record.Name}
+		if config.ConfigName == "" {
+			config.ConfigName = record.ID
+		}
+		var includes []string
+		for _, field := range record.Field {
+			if field.Source != nil && field.Source.FileSet != nil {
+				includes = fileSets[field.Source.FileSet.ID].Includes
+				break
+			}
+		}
+		for _, split := range c.Splits(config.ConfigName) {
+			dataFile := DataFile{Split: split}
+			for _, include := range includes {
+				// Files of all splits are included as "<config>/*/*.parquet", and splits too big to be fully
+				// converted are stored under a "partial-" prefix.
+				dir := fmt.Sprintf("/{%s,partial-%s}/", split, split)
+				dataFile.Paths = append(dataFile.Paths, strings.Replace(include, "/*/", dir, 1))
+			}
+			if len(dataFile.Paths) > 0 {
+				dataFile.Path = dataFile.Paths[0]
+			}
+			if len(dataFile.Paths) == 1 {
+				dataFile.Paths = nil
+			}
+			config.DataFiles = append(config.DataFiles, dataFile)
+		}
+		cardData.Configs = append(cardData.Configs, config)
+	}
+	return cardData
+}
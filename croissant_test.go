@@ -0,0 +1,74 @@
+package huggo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const croissantFixture = `{
+	"@context": {"@vocab": "https://schema.org/", "cr": "http://mlcommons.org/croissant/"},
+	"@type": "sc:Dataset",
+	"name": "imdb",
+	"conformsTo": "http://mlcommons.org/croissant/1.0",
+	"license": "https://choosealicense.com/licenses/other/",
+	"keywords": ["sentiment", "English"],
+	"distribution": [
+		{"@type": "cr:FileObject", "@id": "repo", "name": "repo", "contentUrl": "https://huggingface.co/datasets/imdb/tree/refs%2Fconvert%2Fparquet", "encodingFormat": "git+https", "sha256": "https://github.com/mlcommons/croissant/issues/80"},
+		{"@type": "cr:FileSet", "@id": "parquet-files-for-config-plain_text", "containedIn": {"@id": "repo"}, "encodingFormat": "application/x-parquet", "includes": "plain_text/*/*.parquet"}
+	],
+	"recordSet": [
+		{
+			"@type": "cr:RecordSet", "dataType": "cr:Split", "key": {"@id": "plain_text_splits/split_name"}, "@id": "plain_text_splits", "name": "plain_text_splits",
+			"field": [{"@type": "cr:Field", "@id": "plain_text_splits/split_name", "dataType": "sc:Text"}],
+			"data": [{"plain_text_splits/split_name": "train"}, {"plain_text_splits/split_name": "test"}]
+		},
+		{
+			"@type": "cr:RecordSet", "@id": "plain_text", "name": "plain_text",
+			"field": [
+				{"@type": "cr:Field", "@id": "plain_text/split", "dataType": "sc:Text", "source": {"fileSet": {"@id": "parquet-files-for-config-plain_text"}, "extract": {"fileProperty": "fullpath"}, "transform": {"regex": "plain_text/(?:partial-)?(train|test)/.+parquet$"}}, "references": {"field": {"@id": "plain_text_splits/split_name"}}},
+				{"@type": "cr:Field", "@id": "plain_text/text", "dataType": "sc:Text", "source": {"fileSet": {"@id": "parquet-files-for-config-plain_text"}, "extract": {"column": "text"}}},
+				{"@type": "cr:Field", "@id": "plain_text/label", "dataType": "sc:Integer", "source": {"fileSet": {"@id": "parquet-files-for-config-plain_text"}, "extract": {"column": "label"}}}
+			]
+		}
+	]
+}`
+
+func TestCroissant_CardData(t *testing.T) {
+	var croissant Croissant
+	if err := json.Unmarshal([]byte(croissantFixture), &croissant); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := croissant.RecordSet[1].Field[2].DataType; !reflect.DeepEqual(got, CroissantValues{"sc:Integer"}) {
+		t.Errorf("Unexpected field data type: %v", got)
+	}
+	if got := croissant.Splits("plain_text"); !reflect.DeepEqual(got, []string{"train", "test"}) {
+		t.Errorf("Splits() = %v", got)
+	}
+
+	cardData := croissant.CardData()
+	if cardData.License != "other" {
+		t.Errorf("Expected license other, got %s", cardData.License)
+	}
+	if cardData.PrettyName == nil || *cardData.PrettyName != "imdb" {
+		t.Errorf("Unexpected pretty name: %v", cardData.PrettyName)
+	}
+	want := []Config{{
+		ConfigName: "plain_text",
+		DataFiles: []DataFile{
+			{Split: "train", Path: "plain_text/{train,partial-train}/*.parquet"},
+			{Split: "test", Path: "plain_text/{test,partial-test}/*.parquet"},
+		},
+	}}
+	if !reflect.DeepEqual(cardData.Configs, want) {
+		t.Errorf("CardData().Configs = %+v, want %+v", cardData.Configs, want)
+	}
+	for _, name := range []string{"plain_text/train/0000.parquet", "plain_text/partial-train/0000.parquet"} {
+		if !matchDataFilesPattern(cardData.Configs[0].DataFiles[0].Path, name) {
+			t.Errorf("Expected train data files to match %s", name)
+		}
+	}
+	if matchDataFilesPattern(cardData.Configs[0].DataFiles[0].Path, "plain_text/test/0000.parquet") {
+		t.Errorf("Expected train data files not to match test files")
+	}
+}
@@ -0,0 +1,341 @@
+package huggo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultSplit is the split of data files declared without one.
+const DefaultSplit = "train"
+
+// DefaultConfigName is the name of the single config of datasets that declare no configs.
+const DefaultConfigName = "default"
+
+// defaultSplits are the splits of the default data files layout, with the words naming them in file and
+// directory names.
+var defaultSplits = []struct {
+	split    string
+	keywords []string
+}{
+	{split: "train", keywords: []string{"train", "training"}},
+	{split: "validation", keywords: []string{"validation", "valid", "dev", "val"}},
+	{split: "test", keywords: []string{"test", "testing", "eval", "evaluation"}},
+}
+
+// defaultDataFileExtensions are the extensions of the files of the default data files layout. Text files are only
+// data files when their directory or file names name a split, as they otherwise are licenses, requirements and the
+// like.
+var defaultDataFileExtensions = []string{".csv", ".tsv", ".json", ".jsonl", ".ndjson", ".parquet", ".arrow", ".txt"}
+
+// ResolvedDataFile is a concrete repository file matched by the data files of a dataset config.
+type ResolvedDataFile struct {
+	Config string
+	Split  string
+	Path   string
+	Size   int64
+	// BlobID is the git blob hash of the file.
+	BlobID string
+	// LFS is set when the file is stored with Git LFS.
+	LFS *LFSInfo
+}
+
+// ResolveDataFiles expands the data files patterns of dataset configs into the files they match in the
+// dataset repository at a revision. An empty revision resolves against DefaultRevision.
+//
+// Patterns follow the Hub's data_files semantics: "*" and "?" do not match "/", "**" matches any number of
+// directories, "{a,b}" matches either alternative, and hidden files or directories (starting with "." or "__")
+// are only matched when the pattern names them explicitly.
+func (r *Repository) ResolveDataFiles(ctx context.Context, id string, revision string, configs []Config) ([]ResolvedDataFile, error) {
+	files, err := r.listRepoFiles(ctx, RepoTypeDataset, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return resolveDataFiles(files, configs)
+}
+
+func resolveDataFiles(files []RepoTreeEntry, configs []Config) ([]ResolvedDataFile, error) {
+	var resolved []ResolvedDataFile
+	for _, config := range configs {
+		seen := make(map[string]bool)
+		for _, dataFile := range config.DataFiles {
+			split := dataFile.Split
+			if split == "" {
+				split = DefaultSplit
+			}
+			for _, pattern := range dataFile.Patterns() {
+				matched := false
+				for _, file := range files {
+					if !matchDataFilesPattern(pattern, file.Path) {
+						continue
+					}
+					matched = true
+					key := split + "\x00" + file.Path
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					resolved = append(resolved, ResolvedDataFile{
+						Config: config.ConfigName,
+						Split:  split,
+						Path:   file.Path,
+						Size:   file.Size,
+						BlobID: file.OID,
+						LFS:    file.LFS,
+					})
+				}
+				if !matched {
+					return nil, fmt.Errorf("no data files match pattern %q of config %q split %q", pattern, config.ConfigName, split)
+				}
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// defaultDataFiles resolves the data files of a dataset that declares no configs, following the Hub's default
+// layout. Files are assigned to the split named by a word of their directory or file names, directories first,
+// e.g. "test/part-0.csv" or "data/validation-00000-of-00001.parquet". Files naming no split are ignored, unless no
+// file names one, in which case every file but text files belongs to DefaultSplit.
+func defaultDataFiles(files []RepoTreeEntry) []ResolvedDataFile {
+	var resolved []ResolvedDataFile
+	named := false
+	for _, file := range files {
+		if !isDefaultDataFile(file.Path) {
+			continue
+		}
+		split := defaultDataFileSplit(file.Path)
+		if split == "" && dataFileExt(file.Path) == ".txt" {
+			continue
+		}
+		named = named || split != ""
+		resolved = append(resolved, ResolvedDataFile{
+			Config: DefaultConfigName,
+			Split:  split,
+			Path:   file.Path,
+			Size:   file.Size,
+			BlobID: file.OID,
+			LFS:    file.LFS,
+		})
+	}
+	if !named {
+		for i := range resolved {
+			resolved[i].Split = DefaultSplit
+		}
+		return resolved
+	}
+	return slices.DeleteFunc(resolved, func(file ResolvedDataFile) bool {
+		return file.Split == ""
+	})
+}
+
+// isDefaultDataFile reports whether a repository file is a data file of the default layout: a visible file with
+// a data extension, optionally compressed.
+func isDefaultDataFile(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if isHiddenPathPart(part) {
+			return false
+		}
+	}
+	return slices.Contains(defaultDataFileExtensions, dataFileExt(name))
+}
+
+// dataFileExt returns the extension of a data file, ignoring the extension of its compression.
+func dataFileExt(name string) string {
+	switch ext := path.Ext(name); ext {
+	case ".gz", ".zst", ".zstd", ".bz2", ".xz":
+		name = strings.TrimSuffix(name, ext)
+	}
+	return path.Ext(name)
+}
+
+// defaultDataFileSplit returns the split named by the directory or file names of a repository file, or an empty
+// string if none names a split. Names are split into words at separators and digits.
+func defaultDataFileSplit(name string) string {
+	for _, part := range strings.Split(name, "/") {
+		words := strings.FieldsFunc(strings.ToLower(part), func(r rune) bool {
+			return strings.ContainsRune("-._ 0123456789", r)
+		})
+		for _, s := range defaultSplits {
+			for _, keyword := range s.keywords {
+				if slices.Contains(words, keyword) {
+					return s.split
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// matchDataFilesPattern reports whether a repository file path matches a data files pattern.
+func matchDataFilesPattern(pattern string, name string) bool {
+	nameParts := strings.Split(name, "/")
+	for _, p := range expandBraces(strings.TrimPrefix(pattern, "./")) {
+		if matchPathParts(strings.Split(p, "/"), nameParts) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPathParts(pattern []string, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchPathParts(pattern[1:], name[i:]) {
+				return true
+			}
+			if i < len(name) && isHiddenPathPart(name[i]) {
+				return false
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if isHiddenPathPart(name[0]) && !isHiddenPathPart(pattern[0]) {
+		return false
+	}
+	ok, err := path.Match(pattern[0], name[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchPathParts(pattern[1:], name[1:])
+}
+
+// isHiddenPathPart reports whether a file or directory name is hidden from data files patterns.
+func isHiddenPathPart(part string) bool {
+	return strings.HasPrefix(part, ".") || strings.HasPrefix(part, "__")
+}
+
+// expandBraces expands brace patterns such as "data/{train,test}.csv" into every alternative.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	depth := 0
+	var alternatives []string
+	last := start + 1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			alternatives = append(alternatives, pattern[last:i])
+			var expanded []string
+			for _, suffix := range expandBraces(pattern[i+1:]) {
+				for _, alternative := range alternatives {
+					for _, infix := range expandBraces(alternative) {
+						expanded = append(expanded, pattern[:start]+infix+suffix)
+					}
+				}
+			}
+			return expanded
+		}
+	}
+	// Unbalanced braces are matched literally.
+	return []string{pattern}
+}
+
+// Patterns returns every path pattern of the data file.
+func (d DataFile) Patterns() []string {
+	if len(d.Paths) > 0 {
+		return d.Paths
+	}
+	if d.Path == "" {
+		return nil
+	}
+	return []string{d.Path}
+}
+
+func (d *DataFile) UnmarshalJSON(data []byte) error {
+	var pattern string
+	if err := json.Unmarshal(data, &pattern); err == nil {
+		*d = DataFile{Split: DefaultSplit, Path: pattern}
+		return nil
+	}
+	var dataFile struct {
+		Split string          `json:"split"`
+		Path  json.RawMessage `json:"path"`
+	}
+	if err := json.Unmarshal(data, &dataFile); err != nil {
+		return fmt.Errorf("unsupported type for DataFile: %s", string(data))
+	}
+	paths, err := unmarshalPatterns(dataFile.Path)
+	if err != nil {
+		return err
+	}
+	*d = DataFile{Split: dataFile.Split}
+	if len(paths) > 0 {
+		d.Path = paths[0]
+	}
+	if len(paths) > 1 {
+		d.Paths = paths
+	}
+	return nil
+}
+
+func (d DataFile) MarshalJSON() ([]byte, error) {
+	type dataFile struct {
+		Split string `json:"split"`
+		Path  any    `json:"path"`
+	}
+	if len(d.Paths) > 0 {
+		return json.Marshal(dataFile{Split: d.Split, Path: d.Paths})
+	}
+	return json.Marshal(dataFile{Split: d.Split, Path: d.Path})
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var config struct {
+		ConfigName string          `json:"config_name"`
+		DataFiles  json.RawMessage `json:"data_files"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	*c = Config{ConfigName: config.ConfigName}
+	if len(config.DataFiles) == 0 || string(config.DataFiles) == "null" {
+		return nil
+	}
+	// data_files is either a pattern, a list of patterns, or a list of split/path pairs.
+	if patterns, err := unmarshalPatterns(config.DataFiles); err == nil {
+		for _, pattern := range patterns {
+			c.DataFiles = append(c.DataFiles, DataFile{Split: DefaultSplit, Path: pattern})
+		}
+		return nil
+	}
+	return json.Unmarshal(config.DataFiles, &c.DataFiles)
+}
+
+// unmarshalPatterns decodes a single pattern or a list of patterns.
+func unmarshalPatterns(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var pattern string
+	if err := json.Unmarshal(data, &pattern); err == nil {
+		return []string{pattern}, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
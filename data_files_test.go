@@ -0,0 +1,181 @@
+package huggo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "data/*.csv", want: []string{"data/*.csv"}},
+		{pattern: "data/{train,test}.csv", want: []string{"data/train.csv", "data/test.csv"}},
+		{pattern: "{a,b}/{c,d}", want: []string{"a/c", "b/c", "a/d", "b/d"}},
+		{pattern: "data/{x,{y,z}}.csv", want: []string{"data/x.csv", "data/y.csv", "data/z.csv"}},
+		{pattern: "data/{train.csv", want: []string{"data/{train.csv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := expandBraces(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandBraces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchDataFilesPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "data/*.csv", name: "data/train.csv", want: true},
+		{pattern: "data/*.csv", name: "data/sub/train.csv", want: false},
+		{pattern: "data/**/*.csv", name: "data/train.csv", want: true},
+		{pattern: "data/**/*.csv", name: "data/a/b/train.csv", want: true},
+		{pattern: "**", name: "a/b/c.json", want: true},
+		{pattern: "**", name: ".git/config", want: false},
+		{pattern: "data/**", name: "data/__pycache__/x.pyc", want: false},
+		{pattern: "data/*", name: "data/.hidden", want: false},
+		{pattern: "data/.*", name: "data/.hidden", want: true},
+		{pattern: "data/train-{00000,00001}-of-*.parquet", name: "data/train-00001-of-00002.parquet", want: true},
+		{pattern: "./data/test?.csv", name: "data/test1.csv", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchDataFilesPattern(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchDataFilesPattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Config
+	}{
+		{
+			name: "single pattern",
+			json: `{"config_name":"default","data_files":"data/*.csv"}`,
+			want: Config{ConfigName: "default", DataFiles: []DataFile{{Split: "train", Path: "data/*.csv"}}},
+		},
+		{
+			name: "list of patterns",
+			json: `{"config_name":"default","data_files":["a.csv","b.csv"]}`,
+			want: Config{ConfigName: "default", DataFiles: []DataFile{{Split: "train", Path: "a.csv"}, {Split: "train", Path: "b.csv"}}},
+		},
+		{
+			name: "splits",
+			json: `{"config_name":"default","data_files":[{"split":"test","path":"test.csv"},{"split":"train","path":["a.csv","b.csv"]}]}`,
+			want: Config{ConfigName: "default", DataFiles: []DataFile{
+				{Split: "test", Path: "test.csv"},
+				{Split: "train", Path: "a.csv", Paths: []string{"a.csv", "b.csv"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			if err := json.Unmarshal([]byte(tt.json), &config); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("UnmarshalJSON() = %+v, want %+v", config, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDataFiles(t *testing.T) {
+	files := []RepoTreeEntry{
+		{Type: "file", Path: "README.md", Size: 10, OID: "readme"},
+		{Type: "file", Path: "data/train-00000-of-00002.parquet", Size: 100, OID: "t0", LFS: &LFSInfo{SHA256: "sha-t0", Size: 100}},
+		{Type: "file", Path: "data/train-00001-of-00002.parquet", Size: 200, OID: "t1", LFS: &LFSInfo{SHA256: "sha-t1", Size: 200}},
+		{Type: "file", Path: "data/test-00000-of-00001.parquet", Size: 50, OID: "e0"},
+	}
+	configs := []Config{{
+		ConfigName: "default",
+		DataFiles: []DataFile{
+			{Split: "train", Path: "data/train-*"},
+			{Split: "test", Path: "data/test-*"},
+		},
+	}}
+
+	resolved, err := resolveDataFiles(files, configs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resolved) != 3 {
+		t.Fatalf("Expected 3 files, got %d", len(resolved))
+	}
+	if got := resolved[1]; got.Split != "train" || got.Path != "data/train-00001-of-00002.parquet" || got.LFS.SHA256 != "sha-t1" {
+		t.Errorf("Unexpected resolved file: %+v", got)
+	}
+	if got := resolved[2]; got.Split != "test" || got.BlobID != "e0" || got.LFS != nil {
+		t.Errorf("Unexpected resolved file: %+v", got)
+	}
+
+	configs[0].DataFiles = append(configs[0].DataFiles, DataFile{Split: "validation", Path: "data/validation-*"})
+	if _, err := resolveDataFiles(files, configs); err == nil {
+		t.Errorf("Expected error for pattern matching no files")
+	}
+}
+
+func TestDefaultDataFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  map[string]string
+	}{
+		{
+			name: "named splits",
+			paths: []string{
+				".gitattributes",
+				"README.md",
+				"data/train-00000-of-00001.parquet",
+				"data/validation-00000-of-00001.parquet",
+				"test/part-0.csv.gz",
+				"LICENSE.txt",
+				"data/dev.txt",
+				"extra.csv",
+			},
+			want: map[string]string{
+				"data/train-00000-of-00001.parquet":      "train",
+				"data/validation-00000-of-00001.parquet": "validation",
+				"test/part-0.csv.gz":                     "test",
+				"data/dev.txt":                           "validation",
+			},
+		},
+		{
+			name:  "no named split",
+			paths: []string{"README.md", "LICENSE.txt", "requirements.txt", "data.jsonl", "more/rows.csv", "__hidden/rows.csv"},
+			want:  map[string]string{"data.jsonl": "train", "more/rows.csv": "train"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []RepoTreeEntry
+			for _, p := range tt.paths {
+				files = append(files, RepoTreeEntry{Type: "file", Path: p})
+			}
+			got := make(map[string]string)
+			for _, file := range defaultDataFiles(files) {
+				if file.Config != DefaultConfigName {
+					t.Errorf("Unexpected config %q for %s", file.Config, file.Path)
+				}
+				got[file.Path] = file.Split
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
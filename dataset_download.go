@@ -0,0 +1,157 @@
+package huggo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+)
+
+// ParquetRevision is the revision holding the parquet files datasets are automatically converted to.
+const ParquetRevision = "refs/convert/parquet"
+
+// DownloadDatasetSplit downloads the data files of one split of a dataset config and returns their local paths.
+// The files are downloaded into the cache (see WithCacheDir), or into dir when set, keeping their repository
+// layout. Files already cached, or present in dir with the expected content, are not downloaded again.
+//
+// Datasets that declare no configs in their card have a single DefaultConfigName config, whose data files follow
+// the default layout of the Hub.
+func (r *Repository) DownloadDatasetSplit(ctx context.Context, id string, config string, split string, dir string) ([]string, error) {
+	commit, files, err := r.resolveDatasetSplit(ctx, id, config, split)
+	if err != nil {
+		return nil, err
+	}
+	return r.downloadDataFiles(ctx, id, commit, files, dir)
+}
+
+// resolveDatasetSplit resolves the data files of one split of a dataset config at the latest commit,
+// and returns that commit along with the files.
+func (r *Repository) resolveDatasetSplit(ctx context.Context, id string, config string, split string) (string, []ResolvedDataFile, error) {
+	dataset, err := r.getDatasetRevision(ctx, id, DefaultRevision)
+	if err != nil {
+		return "", nil, err
+	}
+	files, err := r.listRepoFiles(ctx, RepoTypeDataset, id, dataset.SHA)
+	if err != nil {
+		return "", nil, err
+	}
+	var resolved []ResolvedDataFile
+	if len(dataset.CardData.Configs) == 0 && config == DefaultConfigName {
+		for _, file := range defaultDataFiles(files) {
+			if file.Split == split {
+				resolved = append(resolved, file)
+			}
+		}
+	} else {
+		// Only the requested split is resolved, as the patterns of other splits are irrelevant here.
+		var configs []Config
+		for _, c := range dataset.CardData.Configs {
+			if c.ConfigName != config {
+				continue
+			}
+			splitConfig := Config{ConfigName: c.ConfigName}
+			for _, dataFile := range c.DataFiles {
+				if dataFile.Split == split || (dataFile.Split == "" && split == DefaultSplit) {
+					splitConfig.DataFiles = append(splitConfig.DataFiles, dataFile)
+				}
+			}
+			configs = append(configs, splitConfig)
+		}
+		if len(configs) == 0 {
+			return "", nil, fmt.Errorf("config %q not found in dataset %s", config, id)
+		}
+		resolved, err = resolveDataFiles(files, configs)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if len(resolved) == 0 {
+		return "", nil, fmt.Errorf("split %q not found in config %q of dataset %s", split, config, id)
+	}
+	return dataset.SHA, resolved, nil
+}
+
+// DownloadDatasetParquetSplit downloads the parquet files one split of a dataset config was automatically
+// converted to and returns their local paths. The files are downloaded into the cache, or into dir when set,
+// as in DownloadDatasetSplit.
+func (r *Repository) DownloadDatasetParquetSplit(ctx context.Context, id string, config string, split string, dir string) ([]string, error) {
+	dataset, err := r.getDatasetRevision(ctx, id, ParquetRevision)
+	if err != nil {
+		return nil, err
+	}
+	files, err := r.listRepoFiles(ctx, RepoTypeDataset, id, dataset.SHA)
+	if err != nil {
+		return nil, err
+	}
+	// Splits too big to be fully converted are stored under a "partial-" prefix.
+	pattern := fmt.Sprintf("%s/{%s,partial-%s}/*.parquet", config, split, split)
+	var resolved []ResolvedDataFile
+	for _, file := range files {
+		if matchDataFilesPattern(pattern, file.Path) {
+			resolved = append(resolved, ResolvedDataFile{
+				Config: config,
+				Split:  split,
+				Path:   file.Path,
+				Size:   file.Size,
+				BlobID: file.OID,
+				LFS:    file.LFS,
+			})
+		}
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no parquet files found for split %q of config %q of dataset %s", split, config, id)
+	}
+	return r.downloadDataFiles(ctx, id, dataset.SHA, resolved, dir)
+}
+
+// getDatasetRevision fetches the information of a dataset at a revision, including the commit it points to.
+func (r *Repository) getDatasetRevision(ctx context.Context, id string, revision string) (*Dataset, error) {
+	var dataset Dataset
+	rawURL := fmt.Sprintf("%s/%s/%s/revision/%s", r.httpClient.baseURL, RepoTypeDataset.apiPath(), id, url.PathEscape(revision))
+	err := r.httpClient.getURL(ctx, rawURL, &dataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset: %w", err)
+	}
+	return &dataset, nil
+}
+
+// downloadDataFiles downloads resolved data files concurrently into the cache or dir, and returns their local paths.
+func (r *Repository) downloadDataFiles(ctx context.Context, id string, commit string, files []ResolvedDataFile, dir string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	paths := make([]string, len(files))
+	errs := make([]error, len(files))
+	sem := make(chan struct{}, DefaultMaxWorkers)
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			digest := treeDigest(file.Size, file.BlobID, file.LFS)
+			if dir == "" {
+				paths[i], errs[i] = r.downloadBlobToCache(ctx, RepoTypeDataset, id, commit, file.Path, digest, nil)
+			} else {
+				paths[i] = filepath.Join(dir, filepath.FromSlash(file.Path))
+				if fileMatches(paths[i], digest) {
+					return
+				}
+				rawURL := r.httpClient.resolveURL(RepoTypeDataset, id, commit, file.Path)
+				if err := r.httpClient.downloadToFile(ctx, rawURL, paths[i], digest, nil); err != nil {
+					errs[i] = fmt.Errorf("failed to download %s: %w", file.Path, err)
+				}
+			}
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
@@ -0,0 +1,149 @@
+package huggo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRepository_DownloadDatasetSplit(t *testing.T) {
+	const commit = "abc123"
+	contents := map[string]string{
+		"data/train.csv":     "text,label\nhello,1\n",
+		"data/test.csv":      "text,label\nbye,0\n",
+		"data/train-2.jsonl": `{"text":"hi","label":1}` + "\n",
+	}
+	var tree []RepoTreeEntry
+	for name, content := range contents {
+		entry := RepoTreeEntry{Type: "file", Path: name, Size: int64(len(content))}
+		if name == "data/train-2.jsonl" {
+			sum := sha256.Sum256([]byte(content))
+			entry.LFS = &LFSInfo{SHA256: hex.EncodeToString(sum[:]), Size: entry.Size}
+		} else {
+			h := newGitBlobHash(entry.Size)
+			h.Write([]byte(content))
+			entry.OID = hex.EncodeToString(h.Sum(nil))
+		}
+		tree = append(tree, entry)
+	}
+
+	var downloads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/datasets/user/dataset/revision/main", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"sha": commit,
+			"cardData": map[string]any{
+				"configs": []map[string]any{{
+					"config_name": "default",
+					"data_files": []map[string]any{
+						{"split": "train", "path": []string{"data/train.csv", "data/train-*.jsonl"}},
+						{"split": "test", "path": "data/test.csv"},
+					},
+				}},
+			},
+		})
+	})
+	mux.HandleFunc("/api/datasets/user/dataset/tree/"+commit, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/datasets/user/dataset/resolve/"+commit+"/", func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		name := r.URL.Path[len("/datasets/user/dataset/resolve/"+commit+"/"):]
+		_, _ = w.Write([]byte(contents[name]))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	dir := t.TempDir()
+
+	paths, err := repository.DownloadDatasetSplit(context.Background(), "user/dataset", "default", "train", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(paths))
+	}
+	for _, p := range paths {
+		rel, _ := filepath.Rel(dir, p)
+		got, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(got) != contents[filepath.ToSlash(rel)] {
+			t.Errorf("Unexpected content for %s: %q", rel, got)
+		}
+	}
+
+	if _, err := repository.DownloadDatasetSplit(context.Background(), "user/dataset", "default", "train", dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if downloads.Load() != 2 {
+		t.Errorf("Expected files already downloaded to be skipped, got %d downloads", downloads.Load())
+	}
+}
+
+func TestRepository_DownloadDatasetSplit_DefaultConfigToCache(t *testing.T) {
+	const commit = "abc123"
+	contents := map[string]string{
+		"README.md":           "# Dataset\n",
+		"data/train-0.csv":    "text,label\nhello,1\n",
+		"data/test-0.csv":     "text,label\nbye,0\n",
+		"data/validation.csv": "text,label\nhey,1\n",
+	}
+	var tree []RepoTreeEntry
+	for name, content := range contents {
+		h := newGitBlobHash(int64(len(content)))
+		h.Write([]byte(content))
+		tree = append(tree, RepoTreeEntry{Type: "file", Path: name, Size: int64(len(content)), OID: hex.EncodeToString(h.Sum(nil))})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/datasets/user/dataset/revision/main", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"sha": commit, "cardData": map[string]any{"license": "mit"}})
+	})
+	mux.HandleFunc("/api/datasets/user/dataset/tree/"+commit, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/datasets/user/dataset/resolve/"+commit+"/", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[len("/datasets/user/dataset/resolve/"+commit+"/"):]
+		_, _ = w.Write([]byte(contents[name]))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"), WithCacheDir(cacheDir))
+	repository := NewRepository(client)
+
+	paths, err := repository.DownloadDatasetSplit(context.Background(), "user/dataset", DefaultConfigName, "test", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(paths))
+	}
+	want := client.cache.SnapshotPath(string(RepoTypeDataset), "user/dataset", commit, "data/test-0.csv")
+	if paths[0] != want {
+		t.Errorf("Expected %s, got %s", want, paths[0])
+	}
+	got, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(got) != contents["data/test-0.csv"] {
+		t.Errorf("Unexpected content %q", got)
+	}
+
+	if _, err := repository.DownloadDatasetSplit(context.Background(), "user/dataset", "other", "test", ""); err == nil {
+		t.Errorf("Expected error for unknown config")
+	}
+}
@@ -0,0 +1,206 @@
+package huggo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DatasetFilter selects and orders the rows returned by the dataset viewer /filter endpoint.
+type DatasetFilter struct {
+	// Where is the condition rows must match. The zero value matches every row.
+	Where Where
+	// OrderBy lists the columns rows are sorted by, in order of precedence.
+	OrderBy []OrderBy
+}
+
+func (f DatasetFilter) orderBy() string {
+	clauses := make([]string, len(f.OrderBy))
+	for i, o := range f.OrderBy {
+		clauses[i] = o.String()
+	}
+	return strings.Join(clauses, ", ")
+}
+
+// OrderBy sorts rows by a column.
+type OrderBy struct {
+	Column string
+	Desc   bool
+}
+
+func (o OrderBy) String() string {
+	if o.Desc {
+		return quoteIdentifier(o.Column) + " DESC"
+	}
+	return quoteIdentifier(o.Column) + " ASC"
+}
+
+// Where is a SQL-like condition on the columns of a dataset split.
+// Conditions are built with Column and combined with And, Or and Not.
+//
+//	Column("label").Eq(1).And(Column("text").Like("%good%"))
+//
+// Values are strings, booleans, numbers or fmt.Stringer. A condition built with any other value, or with a
+// NaN or infinite float, is invalid and reports it through Err.
+type Where struct {
+	expr string
+	err  error
+}
+
+func (w Where) String() string {
+	return w.expr
+}
+
+// Err returns the reason why the condition is invalid, or nil if it is valid.
+func (w Where) Err() error {
+	return w.err
+}
+
+// And returns a condition matching rows that match both w and other.
+func (w Where) And(other Where) Where {
+	return combineWhere("AND", w, other)
+}
+
+// Or returns a condition matching rows that match either w or other.
+func (w Where) Or(other Where) Where {
+	return combineWhere("OR", w, other)
+}
+
+// Not returns a condition matching rows that do not match w.
+func Not(w Where) Where {
+	if w.expr == "" {
+		return w
+	}
+	return Where{expr: "NOT (" + w.expr + ")", err: w.err}
+}
+
+func combineWhere(op string, left Where, right Where) Where {
+	switch {
+	case left.expr == "":
+		return right
+	case right.expr == "":
+		return left
+	}
+	return Where{expr: "(" + left.expr + ") " + op + " (" + right.expr + ")", err: errors.Join(left.err, right.err)}
+}
+
+// WhereColumn is a column on which conditions are built.
+type WhereColumn struct {
+	name string
+}
+
+// Column returns the column on which to build a condition.
+func Column(name string) WhereColumn {
+	return WhereColumn{name: name}
+}
+
+// Eq matches rows where the column is equal to value. A nil value matches rows where the column is null.
+func (c WhereColumn) Eq(value any) Where {
+	if value == nil {
+		return c.IsNull()
+	}
+	return c.compare("=", value)
+}
+
+// Ne matches rows where the column is not equal to value. A nil value matches rows where the column is not null.
+func (c WhereColumn) Ne(value any) Where {
+	if value == nil {
+		return c.IsNotNull()
+	}
+	return c.compare("!=", value)
+}
+
+// Lt matches rows where the column is lower than value.
+func (c WhereColumn) Lt(value any) Where {
+	return c.compare("<", value)
+}
+
+// Le matches rows where the column is lower than or equal to value.
+func (c WhereColumn) Le(value any) Where {
+	return c.compare("<=", value)
+}
+
+// Gt matches rows where the column is greater than value.
+func (c WhereColumn) Gt(value any) Where {
+	return c.compare(">", value)
+}
+
+// Ge matches rows where the column is greater than or equal to value.
+func (c WhereColumn) Ge(value any) Where {
+	return c.compare(">=", value)
+}
+
+// Like matches rows where the column matches a SQL LIKE pattern, e.g. "%word%".
+func (c WhereColumn) Like(pattern string) Where {
+	return c.compare("LIKE", pattern)
+}
+
+// In matches rows where the column is equal to one of values, of which there must be at least one.
+func (c WhereColumn) In(values ...any) Where {
+	literals := make([]string, len(values))
+	var errs []error
+	if len(values) == 0 {
+		errs = append(errs, fmt.Errorf("no values to match column %q against", c.name))
+	}
+	for i, value := range values {
+		literal, err := quoteLiteral(value)
+		literals[i] = literal
+		errs = append(errs, err)
+	}
+	return Where{expr: quoteIdentifier(c.name) + " IN (" + strings.Join(literals, ", ") + ")", err: errors.Join(errs...)}
+}
+
+// IsNull matches rows where the column is null.
+func (c WhereColumn) IsNull() Where {
+	return Where{expr: quoteIdentifier(c.name) + " IS NULL"}
+}
+
+// IsNotNull matches rows where the column is not null.
+func (c WhereColumn) IsNotNull() Where {
+	return Where{expr: quoteIdentifier(c.name) + " IS NOT NULL"}
+}
+
+func (c WhereColumn) compare(op string, value any) Where {
+	literal, err := quoteLiteral(value)
+	return Where{expr: quoteIdentifier(c.name) + " " + op + " " + literal, err: err}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral formats a value as a SQL literal. Values SQL has no literal for are rejected.
+func quoteLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case fmt.Stringer:
+		return quoteLiteral(v.String())
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "NULL", fmt.Errorf("unsupported value %v", f)
+		}
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), nil
+	case reflect.String:
+		return quoteLiteral(rv.String())
+	case reflect.Bool:
+		return quoteLiteral(rv.Bool())
+	}
+	return "NULL", fmt.Errorf("unsupported value %v of type %T", value, value)
+}
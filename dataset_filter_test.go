@@ -0,0 +1,101 @@
+package huggo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWhere_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		where Where
+		want  string
+	}{
+		{
+			name:  "zero value",
+			where: Where{},
+			want:  "",
+		},
+		{
+			name:  "number",
+			where: Column("label").Eq(1),
+			want:  `"label" = 1`,
+		},
+		{
+			name:  "escaped string",
+			where: Column("text").Like("%it's%"),
+			want:  `"text" LIKE '%it''s%'`,
+		},
+		{
+			name:  "escaped column",
+			where: Column(`my "col"`).IsNull(),
+			want:  `"my ""col""" IS NULL`,
+		},
+		{
+			name:  "in",
+			where: Column("lang").In("en", "fr"),
+			want:  `"lang" IN ('en', 'fr')`,
+		},
+		{
+			name:  "combined",
+			where: Column("score").Ge(0.5).And(Not(Column("flagged").Eq(true)).Or(Column("label").Ne(nil))),
+			want:  `("score" >= 0.5) AND ((NOT ("flagged" = TRUE)) OR ("label" IS NOT NULL))`,
+		},
+		{
+			name:  "equal to nil",
+			where: Column("label").Eq(nil),
+			want:  `"label" IS NULL`,
+		},
+		{
+			name:  "float",
+			where: Column("score").Lt(float32(0.1)),
+			want:  `"score" < 0.1`,
+		},
+		{
+			name:  "combined with zero value",
+			where: Where{}.And(Column("label").Lt(3)),
+			want:  `"label" < 3`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.where.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if err := tt.where.Err(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWhere_Err(t *testing.T) {
+	tests := []struct {
+		name  string
+		where Where
+	}{
+		{name: "slice", where: Column("label").Eq([]int{1, 2})},
+		{name: "map", where: Column("label").Ne(map[string]int{})},
+		{name: "NaN", where: Column("score").Gt(math.NaN())},
+		{name: "infinity", where: Column("score").Lt(math.Inf(1))},
+		{name: "nil in list", where: Column("label").In(1, nil)},
+		{name: "empty list", where: Column("label").In()},
+		{name: "combined", where: Not(Column("label").Eq(1).Or(Column("score").Ge(math.Inf(-1))))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.where.Err() == nil {
+				t.Errorf("Expected error for %s", tt.where)
+			}
+		})
+	}
+}
+
+func TestDatasetFilter_orderBy(t *testing.T) {
+	filter := DatasetFilter{OrderBy: []OrderBy{{Column: "score", Desc: true}, {Column: "id"}}}
+	if got, want := filter.orderBy(), `"score" DESC, "id" ASC`; got != want {
+		t.Errorf("orderBy() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,499 @@
+package huggo
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxResumeRetries is the number of times in a row an interrupted transfer is resumed before giving up.
+const maxResumeRetries = 3
+
+// OpenDatasetFile opens a file of a dataset repository at a revision for streaming, transparently resuming
+// interrupted transfers with Range requests. The content is returned as stored, without decompression.
+func (r *Repository) OpenDatasetFile(ctx context.Context, id string, revision string, filename string) (io.ReadCloser, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	reader := &resumableReader{
+		ctx:        ctx,
+		httpClient: r.httpClient,
+		url:        r.httpClient.resolveURL(RepoTypeDataset, id, revision, filename),
+	}
+	if err := reader.open(); err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+	return reader, nil
+}
+
+// ReadDatasetRows streams the rows of dataset files at a revision, file after file, decoding each row into T.
+//
+// JSON Lines (.jsonl, .ndjson) and CSV (.csv, .tsv) files are supported, optionally compressed with gzip (.gz)
+// or zstd (.zst, .zstd). T is typically map[string]any or a struct whose fields are tagged with column names.
+// Values of CSV files decoded into maps are converted to int64, float64 or bool when every value of their column
+// within the first rows looks like so.
+func ReadDatasetRows[T any](ctx context.Context, r *Repository, id string, revision string, filenames ...string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, filename := range filenames {
+			if !readDatasetFileRows(ctx, r, id, revision, filename, yield) {
+				return
+			}
+		}
+	}
+}
+
+// ReadDatasetSplitRows streams the rows of every data file of one split of a dataset config, decoding each
+// row into T. See ReadDatasetRows for the supported formats.
+func ReadDatasetSplitRows[T any](ctx context.Context, r *Repository, id string, config string, split string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		commit, files, err := r.resolveDatasetSplit(ctx, id, config, split)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for _, file := range files {
+			if !readDatasetFileRows(ctx, r, id, commit, file.Path, yield) {
+				return
+			}
+		}
+	}
+}
+
+// readDatasetFileRows yields the rows of a single file and reports whether iteration should continue.
+func readDatasetFileRows[T any](ctx context.Context, r *Repository, id string, revision string, filename string, yield func(T, error) bool) bool {
+	var zero T
+	body, err := r.OpenDatasetFile(ctx, id, revision, filename)
+	if err != nil {
+		yield(zero, err)
+		return false
+	}
+	defer body.Close()
+
+	rows, err := newRowReader(filename, body)
+	if err != nil {
+		yield(zero, fmt.Errorf("failed to read %s: %w", filename, err))
+		return false
+	}
+	defer rows.Close()
+	for {
+		var row T
+		err := rows.Next(&row)
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+		if err != nil {
+			yield(zero, fmt.Errorf("failed to read %s: %w", filename, err))
+			return false
+		}
+		if !yield(row, nil) {
+			return false
+		}
+	}
+}
+
+// rowReader decodes the rows of a dataset file one by one.
+type rowReader interface {
+	// Next decodes the next row into v, returning io.EOF after the last row.
+	Next(v any) error
+	Close() error
+}
+
+// newRowReader returns a reader of the rows of a dataset file, detecting the compression and format from its name.
+func newRowReader(filename string, r io.Reader) (rowReader, error) {
+	var closer io.Closer
+	switch ext := path.Ext(filename); ext {
+	case ".gz":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r, closer = gz, gz
+		filename = strings.TrimSuffix(filename, ext)
+	case ".zst", ".zstd":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r, closer = zr, zstdCloser{zr}
+		filename = strings.TrimSuffix(filename, ext)
+	}
+
+	switch ext := path.Ext(filename); ext {
+	case ".jsonl", ".ndjson":
+		return &jsonLinesReader{dec: json.NewDecoder(r), closer: closer}, nil
+	case ".csv", ".tsv":
+		cr := csv.NewReader(r)
+		if ext == ".tsv" {
+			cr.Comma = '\t'
+		}
+		cr.ReuseRecord = true
+		header, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		return &csvRowReader{r: cr, header: append([]string(nil), header...), closer: closer}, nil
+	default:
+		return nil, fmt.Errorf("unsupported file format %q", ext)
+	}
+}
+
+type zstdCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+type jsonLinesReader struct {
+	dec    *json.Decoder
+	closer io.Closer
+}
+
+func (r *jsonLinesReader) Next(v any) error {
+	return r.dec.Decode(v)
+}
+
+func (r *jsonLinesReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// csvInferenceRows is the number of rows read ahead to infer the type of the columns of a CSV file.
+const csvInferenceRows = 1000
+
+type csvRowReader struct {
+	r      *csv.Reader
+	header []string
+	closer io.Closer
+	// kinds are the types of the columns, inferred from the rows read ahead in buffered.
+	kinds    []csvKind
+	buffered [][]string
+}
+
+func (r *csvRowReader) Next(v any) error {
+	if r.kinds == nil {
+		if err := r.inferKinds(); err != nil {
+			return err
+		}
+	}
+	if len(r.buffered) > 0 {
+		record := r.buffered[0]
+		r.buffered = r.buffered[1:]
+		return decodeCSVRecord(r.header, record, r.kinds, v)
+	}
+	record, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	return decodeCSVRecord(r.header, record, r.kinds, v)
+}
+
+// inferKinds reads up to csvInferenceRows rows ahead and infers the type of every column from them.
+func (r *csvRowReader) inferKinds() error {
+	r.kinds = make([]csvKind, len(r.header))
+	for range csvInferenceRows {
+		record, err := r.r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i, cell := range record {
+			if i < len(r.kinds) {
+				r.kinds[i] = r.kinds[i].merge(inferCSVKind(cell))
+			}
+		}
+		r.buffered = append(r.buffered, slices.Clone(record))
+	}
+	return nil
+}
+
+func (r *csvRowReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// decodeCSVRecord decodes a CSV record into v, which is either a map or a pointer to a struct.
+// Cells decoded into maps are converted to the type of their column, see inferCSVKind.
+func decodeCSVRecord(header []string, record []string, kinds []csvKind, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot decode CSV record into %T", v)
+	}
+	rv = rv.Elem()
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode CSV record into %T", v)
+		}
+		elem := rv.Type().Elem()
+		row := reflect.MakeMapWithSize(rv.Type(), len(header))
+		for i, column := range header {
+			var cell string
+			if i < len(record) {
+				cell = record[i]
+			}
+			value := reflect.Zero(elem)
+			if inferred := kinds[i].convert(cell); inferred != nil {
+				value = reflect.ValueOf(inferred)
+				if !value.Type().AssignableTo(elem) {
+					value = reflect.ValueOf(cell)
+				}
+			}
+			if !value.Type().AssignableTo(elem) {
+				return fmt.Errorf("cannot decode CSV record into %T", v)
+			}
+			row.SetMapIndex(reflect.ValueOf(column).Convert(rv.Type().Key()), value)
+		}
+		rv.Set(row)
+		return nil
+	case reflect.Struct:
+		fields := csvStructFields(rv.Type())
+		for i, column := range header {
+			index, ok := fields[column]
+			if !ok || i >= len(record) {
+				continue
+			}
+			if err := setCSVField(rv.FieldByIndex(index), record[i]); err != nil {
+				return fmt.Errorf("failed to decode column %q: %w", column, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot decode CSV record into %T", v)
+}
+
+// csvStructFields maps column names to struct fields, using their json tag name when set.
+func csvStructFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int, t.NumField())
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		fields[name] = field.Index
+	}
+	return fields
+}
+
+func setCSVField(field reflect.Value, cell string) error {
+	if cell == "" && field.Kind() != reflect.String {
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		// Nested values such as lists are expected to be serialized as JSON.
+		return json.Unmarshal([]byte(cell), field.Addr().Interface())
+	}
+	return nil
+}
+
+// csvKind is the type of the values of a CSV column.
+type csvKind int
+
+const (
+	csvKindNull csvKind = iota
+	csvKindInt
+	csvKindFloat
+	csvKindBool
+	csvKindString
+)
+
+// inferCSVKind returns the type of a CSV cell: an int64, float64 or bool when it looks like so, or a string.
+// Empty cells are null.
+func inferCSVKind(cell string) csvKind {
+	if cell == "" {
+		return csvKindNull
+	}
+	// Numbers with leading zeros such as zip codes are kept as strings, as well as words ParseFloat accepts
+	// such as "NaN" or "Inf".
+	if isCSVNumber(cell) && (len(cell) == 1 || cell[0] != '0' || strings.HasPrefix(cell, "0.")) {
+		if _, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			return csvKindInt
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			return csvKindFloat
+		}
+	}
+	if _, ok := parseCSVBool(cell); ok {
+		return csvKindBool
+	}
+	return csvKindString
+}
+
+// merge returns the type of a column holding values of both types: integers and floats are floats, and
+// other mixed types are strings.
+func (k csvKind) merge(other csvKind) csvKind {
+	switch {
+	case k == other || other == csvKindNull:
+		return k
+	case k == csvKindNull:
+		return other
+	case (k == csvKindInt || k == csvKindFloat) && (other == csvKindInt || other == csvKindFloat):
+		return csvKindFloat
+	}
+	return csvKindString
+}
+
+// convert converts a CSV cell to the type of its column, or returns it unchanged if it does not parse as such,
+// which only happens for cells past the rows types are inferred from. Empty cells are converted to nil.
+func (k csvKind) convert(cell string) any {
+	if cell == "" {
+		return nil
+	}
+	switch k {
+	case csvKindInt:
+		if n, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			return n
+		}
+	case csvKindFloat:
+		if f, err := strconv.ParseFloat(cell, 64); err == nil && isCSVNumber(cell) {
+			return f
+		}
+	case csvKindBool:
+		if b, ok := parseCSVBool(cell); ok {
+			return b
+		}
+	}
+	return cell
+}
+
+// isCSVNumber reports whether a cell is made of digits, signs, dots and exponents only.
+func isCSVNumber(cell string) bool {
+	return strings.ContainsAny(cell, "0123456789") && strings.Trim(cell, "0123456789+-.eE") == ""
+}
+
+func parseCSVBool(cell string) (bool, bool) {
+	switch cell {
+	case "true", "True", "TRUE":
+		return true, true
+	case "false", "False", "FALSE":
+		return false, true
+	}
+	return false, false
+}
+
+// resumableReader streams the content at a URL, resuming with a Range request when the transfer is interrupted.
+type resumableReader struct {
+	ctx        context.Context
+	httpClient *HttpClient
+	url        string
+	body       io.ReadCloser
+	offset     int64
+	retries    int
+}
+
+func (r *resumableReader) open() error {
+	req, err := r.httpClient.newURLRequest(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	if r.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+	}
+	resp, err := r.httpClient.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && r.offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range, so the content already read is skipped.
+		if _, err := io.CopyN(io.Discard, resp.Body, r.offset); err != nil {
+			resp.Body.Close()
+			return err
+		}
+	default:
+		defer resp.Body.Close()
+		return newAPIError(resp)
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	for {
+		if r.body == nil {
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			r.retries = 0
+		}
+		if err == nil || errors.Is(err, io.EOF) || r.ctx.Err() != nil || r.retries >= maxResumeRetries {
+			return n, err
+		}
+		r.body.Close()
+		r.body = nil
+		r.retries++
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *resumableReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
@@ -0,0 +1,150 @@
+package huggo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestReadDatasetRows(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write([]byte(`{"text":"hello","label":1}` + "\n" + `{"text":"world","label":0}` + "\n"))
+	_ = gz.Close()
+
+	var zstded bytes.Buffer
+	zw, _ := zstd.NewWriter(&zstded)
+	_, _ = zw.Write([]byte("text,label\nfoo,1\nbar,\n"))
+	_ = zw.Close()
+
+	files := map[string][]byte{
+		"train-0.jsonl.gz": gzipped.Bytes(),
+		"train-1.csv.zst":  zstded.Bytes(),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/datasets/user/dataset/resolve/main/")
+		_, _ = w.Write(files[name])
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	type row struct {
+		Text  string `json:"text"`
+		Label *int   `json:"label"`
+	}
+	var rows []row
+	for r, err := range ReadDatasetRows[row](context.Background(), repository, "user/dataset", "", "train-0.jsonl.gz", "train-1.csv.zst") {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows = append(rows, r)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows, got %d", len(rows))
+	}
+	if rows[2].Text != "foo" || rows[2].Label == nil || *rows[2].Label != 1 {
+		t.Errorf("Unexpected row: %+v", rows[2])
+	}
+	if rows[3].Text != "bar" || rows[3].Label != nil {
+		t.Errorf("Unexpected row: %+v", rows[3])
+	}
+
+	var maps []map[string]any
+	for r, err := range ReadDatasetRows[map[string]any](context.Background(), repository, "user/dataset", "", "train-1.csv.zst") {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		maps = append(maps, r)
+	}
+	if len(maps) != 2 || maps[0]["label"] != int64(1) || maps[1]["label"] != nil {
+		t.Errorf("Unexpected rows: %v", maps)
+	}
+}
+
+func TestOpenDatasetFile_Resume(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+		if start > 0 {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		// Every response is cut short after 3000 bytes.
+		end := min(start+3000, len(content))
+		_, _ = w.Write([]byte(content[start:end]))
+		if end < len(content) {
+			panic(http.ErrAbortHandler)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	f, err := repository.OpenDatasetFile(context.Background(), "user/dataset", "main", "data.txt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+	var got bytes.Buffer
+	if _, err := got.ReadFrom(f); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.String() != content {
+		t.Errorf("Expected %d bytes, got %d", len(content), got.Len())
+	}
+	if requests.Load() != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests.Load())
+	}
+}
+
+func TestCSVRowReader_InferTypes(t *testing.T) {
+	data := "id,score,name,zip,flag\n" +
+		"1,1,Nan,02134,true\n" +
+		"2,2.5,Inf,10001,False\n" +
+		"3,,infinity,94105,\n"
+	r, err := newRowReader("train.csv", strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	want := []map[string]any{
+		{"id": int64(1), "score": 1.0, "name": "Nan", "zip": "02134", "flag": true},
+		{"id": int64(2), "score": 2.5, "name": "Inf", "zip": "10001", "flag": false},
+		{"id": int64(3), "score": nil, "name": "infinity", "zip": "94105", "flag": nil},
+	}
+	for i, w := range want {
+		var row map[string]any
+		if err := r.Next(&row); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for column, value := range w {
+			if row[column] != value {
+				t.Errorf("Expected %s of row %d to be %#v, got %#v", column, i, value, row[column])
+			}
+		}
+	}
+	var row map[string]any
+	if err := r.Next(&row); err != io.EOF {
+		t.Errorf("Expected EOF, got %v", err)
+	}
+}
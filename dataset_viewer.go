@@ -0,0 +1,487 @@
+package huggo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// MaxDatasetRowsPerPage is the maximum number of rows the dataset viewer returns per request.
+const MaxDatasetRowsPerPage = 100
+
+type DatasetViewer struct {
+	httpClient *HttpClient
+}
+
+// NewDatasetViewer creates a client to interact with the dataset viewer API (datasets-server).
+func NewDatasetViewer(httpClient *HttpClient) *DatasetViewer {
+	return &DatasetViewer{httpClient: httpClient}
+}
+
+// IsValid checks which dataset viewer features are available for a dataset.
+func (v *DatasetViewer) IsValid(ctx context.Context, dataset string) (*DatasetValidity, error) {
+	var validity DatasetValidity
+	err := v.get(ctx, "/is-valid", url.Values{"dataset": {dataset}}, &validity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dataset validity: %w", err)
+	}
+	return &validity, nil
+}
+
+// GetSplits fetches the configs and splits of a dataset.
+func (v *DatasetViewer) GetSplits(ctx context.Context, dataset string) (*DatasetSplits, error) {
+	var splits DatasetSplits
+	err := v.get(ctx, "/splits", url.Values{"dataset": {dataset}}, &splits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset splits: %w", err)
+	}
+	return &splits, nil
+}
+
+// GetInfo fetches the description, features and splits of a dataset config.
+func (v *DatasetViewer) GetInfo(ctx context.Context, dataset string, config string) (*DatasetInfo, error) {
+	var info struct {
+		DatasetInfo DatasetInfo `json:"dataset_info"`
+		Partial     bool        `json:"partial"`
+	}
+	params := url.Values{"dataset": {dataset}, "config": {config}}
+	err := v.get(ctx, "/info", params, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset info: %w", err)
+	}
+	info.DatasetInfo.Partial = info.Partial
+	return &info.DatasetInfo, nil
+}
+
+// GetFirstRows fetches the features and the first rows of a dataset split.
+func (v *DatasetViewer) GetFirstRows(ctx context.Context, dataset string, config string, split string) (*DatasetFirstRows, error) {
+	var rows DatasetFirstRows
+	params := url.Values{"dataset": {dataset}, "config": {config}, "split": {split}}
+	err := v.get(ctx, "/first-rows", params, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset first rows: %w", err)
+	}
+	return &rows, nil
+}
+
+// GetRows fetches a page of at most MaxDatasetRowsPerPage rows of a dataset split, starting at offset.
+func (v *DatasetViewer) GetRows(ctx context.Context, dataset string, config string, split string, offset int, length int) (*DatasetRowsPage, error) {
+	var page DatasetRowsPage
+	params := url.Values{
+		"dataset": {dataset},
+		"config":  {config},
+		"split":   {split},
+		"offset":  {strconv.Itoa(offset)},
+		"length":  {strconv.Itoa(length)},
+	}
+	err := v.get(ctx, "/rows", params, &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset rows: %w", err)
+	}
+	return &page, nil
+}
+
+// IterRows iterates over the rows of a dataset split starting at offset, fetching them page by page.
+func (v *DatasetViewer) IterRows(ctx context.Context, dataset string, config string, split string, offset int) iter.Seq2[DatasetRow, error] {
+	return iterRowsPages(offset, func(offset int) (*DatasetRowsPage, error) {
+		return v.GetRows(ctx, dataset, config, split, offset, MaxDatasetRowsPerPage)
+	})
+}
+
+// SearchRows fetches a page of at most MaxDatasetRowsPerPage rows of a dataset split matching a full-text query.
+func (v *DatasetViewer) SearchRows(ctx context.Context, dataset string, config string, split string, query string, offset int, length int) (*DatasetRowsPage, error) {
+	var page DatasetRowsPage
+	params := url.Values{
+		"dataset": {dataset},
+		"config":  {config},
+		"split":   {split},
+		"query":   {query},
+		"offset":  {strconv.Itoa(offset)},
+		"length":  {strconv.Itoa(length)},
+	}
+	err := v.get(ctx, "/search", params, &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search dataset rows: %w", err)
+	}
+	return &page, nil
+}
+
+// IterSearchRows iterates over the rows of a dataset split matching a full-text query, fetching them page by page.
+func (v *DatasetViewer) IterSearchRows(ctx context.Context, dataset string, config string, split string, query string, offset int) iter.Seq2[DatasetRow, error] {
+	return iterRowsPages(offset, func(offset int) (*DatasetRowsPage, error) {
+		return v.SearchRows(ctx, dataset, config, split, query, offset, MaxDatasetRowsPerPage)
+	})
+}
+
+// FilterRows fetches a page of at most MaxDatasetRowsPerPage rows of a dataset split matching a filter.
+func (v *DatasetViewer) FilterRows(ctx context.Context, dataset string, config string, split string, filter DatasetFilter, offset int, length int) (*DatasetRowsPage, error) {
+	var page DatasetRowsPage
+	params := url.Values{
+		"dataset": {dataset},
+		"config":  {config},
+		"split":   {split},
+		"offset":  {strconv.Itoa(offset)},
+		"length":  {strconv.Itoa(length)},
+	}
+	if err := filter.Where.Err(); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if where := filter.Where.String(); where != "" {
+		params.Set("where", where)
+	}
+	if orderBy := filter.orderBy(); orderBy != "" {
+		params.Set("orderby", orderBy)
+	}
+	err := v.get(ctx, "/filter", params, &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter dataset rows: %w", err)
+	}
+	return &page, nil
+}
+
+// IterFilterRows iterates over the rows of a dataset split matching a filter, fetching them page by page.
+func (v *DatasetViewer) IterFilterRows(ctx context.Context, dataset string, config string, split string, filter DatasetFilter, offset int) iter.Seq2[DatasetRow, error] {
+	return iterRowsPages(offset, func(offset int) (*DatasetRowsPage, error) {
+		return v.FilterRows(ctx, dataset, config, split, filter, offset, MaxDatasetRowsPerPage)
+	})
+}
+
+// GetSize fetches the size of a dataset, its configs and its splits.
+func (v *DatasetViewer) GetSize(ctx context.Context, dataset string) (*DatasetSize, error) {
+	var size DatasetSize
+	err := v.get(ctx, "/size", url.Values{"dataset": {dataset}}, &size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset size: %w", err)
+	}
+	return &size, nil
+}
+
+// GetStatistics fetches the statistics of every column of a dataset split.
+func (v *DatasetViewer) GetStatistics(ctx context.Context, dataset string, config string, split string) (*DatasetStatistics, error) {
+	var statistics DatasetStatistics
+	params := url.Values{"dataset": {dataset}, "config": {config}, "split": {split}}
+	err := v.get(ctx, "/statistics", params, &statistics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset statistics: %w", err)
+	}
+	return &statistics, nil
+}
+
+// GetParquetFiles fetches the list of parquet files the dataset was converted to.
+func (v *DatasetViewer) GetParquetFiles(ctx context.Context, dataset string) (*DatasetParquetFiles, error) {
+	var files DatasetParquetFiles
+	err := v.get(ctx, "/parquet", url.Values{"dataset": {dataset}}, &files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset parquet files: %w", err)
+	}
+	return &files, nil
+}
+
+func (v *DatasetViewer) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	rawURL := v.httpClient.datasetsServerURL + path + "?" + params.Encode()
+	return v.httpClient.getURL(ctx, rawURL, out)
+}
+
+// iterRowsPages iterates over paginated rows, fetching pages until every row has been yielded.
+func iterRowsPages(offset int, fetch func(offset int) (*DatasetRowsPage, error)) iter.Seq2[DatasetRow, error] {
+	return func(yield func(DatasetRow, error) bool) {
+		for {
+			page, err := fetch(offset)
+			if err != nil {
+				yield(DatasetRow{}, err)
+				return
+			}
+			for _, row := range page.Rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			offset += len(page.Rows)
+			if len(page.Rows) == 0 || int64(offset) >= page.NumRowsTotal {
+				return
+			}
+		}
+	}
+}
+
+type DatasetValidity struct {
+	Preview    bool `json:"preview"`
+	Viewer     bool `json:"viewer"`
+	Search     bool `json:"search"`
+	Filter     bool `json:"filter"`
+	Statistics bool `json:"statistics"`
+}
+
+type DatasetSplits struct {
+	Splits  []DatasetSplit        `json:"splits"`
+	Pending []DatasetSplit        `json:"pending"`
+	Failed  []DatasetSplitFailure `json:"failed"`
+}
+
+type DatasetSplit struct {
+	Dataset string `json:"dataset"`
+	Config  string `json:"config"`
+	Split   string `json:"split"`
+}
+
+type DatasetSplitFailure struct {
+	Dataset string `json:"dataset"`
+	Config  string `json:"config"`
+	Split   string `json:"split,omitempty"`
+	Error   any    `json:"error"`
+}
+
+type DatasetInfo struct {
+	Description  string                      `json:"description"`
+	Citation     string                      `json:"citation"`
+	Homepage     string                      `json:"homepage"`
+	License      string                      `json:"license"`
+	Features     map[string]*FeatureType     `json:"features"`
+	BuilderName  string                      `json:"builder_name"`
+	DatasetName  string                      `json:"dataset_name"`
+	ConfigName   string                      `json:"config_name"`
+	Version      DatasetInfoVersion          `json:"version"`
+	Splits       map[string]DatasetInfoSplit `json:"splits"`
+	DownloadSize int64                       `json:"download_size"`
+	DatasetSize  int64                       `json:"dataset_size"`
+	Partial      bool                        `json:"-"`
+}
+
+type DatasetInfoVersion struct {
+	VersionStr string `json:"version_str"`
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+}
+
+type DatasetInfoSplit struct {
+	Name        string `json:"name"`
+	NumBytes    int64  `json:"num_bytes"`
+	NumExamples int64  `json:"num_examples"`
+	DatasetName string `json:"dataset_name"`
+}
+
+// Feature types as found in the "_type" field of a feature.
+const (
+	FeatureTypeValue       = "Value"
+	FeatureTypeClassLabel  = "ClassLabel"
+	FeatureTypeSequence    = "Sequence"
+	FeatureTypeList        = "List"
+	FeatureTypeLargeList   = "LargeList"
+	FeatureTypeImage       = "Image"
+	FeatureTypeAudio       = "Audio"
+	FeatureTypeVideo       = "Video"
+	FeatureTypePdf         = "Pdf"
+	FeatureTypeTranslation = "Translation"
+	FeatureTypeArray2D     = "Array2D"
+	FeatureTypeArray3D     = "Array3D"
+	FeatureTypeArray4D     = "Array4D"
+	FeatureTypeArray5D     = "Array5D"
+	// FeatureTypeDict is used for features made of named sub-features, which have no "_type" field.
+	FeatureTypeDict = "Dict"
+	// FeatureTypeTranslationVariableLanguages is used for translations with a variable set of languages.
+	FeatureTypeTranslationVariableLanguages = "TranslationVariableLanguages"
+)
+
+// FeatureType describes the type of a dataset column.
+type FeatureType struct {
+	// Type is the kind of feature, e.g. FeatureTypeValue or FeatureTypeClassLabel.
+	Type string `json:"_type"`
+	// Dtype is the data type of Value and ArrayND features, e.g. "string" or "int64".
+	Dtype string `json:"dtype,omitempty"`
+	// Names are the class names of ClassLabel features.
+	Names []string `json:"names,omitempty"`
+	// Feature is the type of the elements of Sequence and List features.
+	Feature *FeatureType `json:"feature,omitempty"`
+	// Length is the fixed length of Sequence and List features, or -1 if it is variable.
+	Length int `json:"length,omitempty"`
+	// Shape is the shape of ArrayND features.
+	Shape []int `json:"shape,omitempty"`
+	// Languages are the languages of Translation features.
+	Languages []string `json:"languages,omitempty"`
+	// NumLanguages is the number of languages of TranslationVariableLanguages features.
+	NumLanguages int `json:"num_languages,omitempty"`
+	// SamplingRate is the sampling rate of Audio features.
+	SamplingRate int `json:"sampling_rate,omitempty"`
+	// Decode tells whether Image and Audio features are decoded.
+	Decode *bool `json:"decode,omitempty"`
+	// Fields are the sub-features of Dict features.
+	Fields map[string]*FeatureType `json:"-"`
+}
+
+func (f *FeatureType) UnmarshalJSON(data []byte) error {
+	var list []*FeatureType
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) != 1 {
+			return fmt.Errorf("unsupported list feature with %d element types", len(list))
+		}
+		*f = FeatureType{Type: FeatureTypeList, Feature: list[0], Length: -1}
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("unsupported type for FeatureType: %s", string(data))
+	}
+	if _, ok := fields["_type"]; !ok {
+		*f = FeatureType{Type: FeatureTypeDict, Fields: make(map[string]*FeatureType, len(fields))}
+		for name, raw := range fields {
+			var field FeatureType
+			if err := json.Unmarshal(raw, &field); err != nil {
+				return err
+			}
+			f.Fields[name] = &field
+		}
+		return nil
+	}
+
+	type featureType FeatureType
+	var ft featureType
+	if err := json.Unmarshal(data, &ft); err != nil {
+		return err
+	}
+	*f = FeatureType(ft)
+	return nil
+}
+
+type DatasetFeature struct {
+	FeatureIdx int          `json:"feature_idx"`
+	Name       string       `json:"name"`
+	Type       *FeatureType `json:"type"`
+}
+
+type DatasetFirstRows struct {
+	Dataset   string           `json:"dataset"`
+	Config    string           `json:"config"`
+	Split     string           `json:"split"`
+	Features  []DatasetFeature `json:"features"`
+	Rows      []DatasetRow     `json:"rows"`
+	Truncated bool             `json:"truncated"`
+}
+
+type DatasetRowsPage struct {
+	Features       []DatasetFeature `json:"features"`
+	Rows           []DatasetRow     `json:"rows"`
+	NumRowsTotal   int64            `json:"num_rows_total"`
+	NumRowsPerPage int64            `json:"num_rows_per_page"`
+	Partial        bool             `json:"partial"`
+}
+
+type DatasetRow struct {
+	RowIdx int64 `json:"row_idx"`
+	// Row maps column names to their raw JSON cell value.
+	Row            map[string]json.RawMessage `json:"row"`
+	TruncatedCells []string                   `json:"truncated_cells"`
+}
+
+// Cell returns the decoded value of a cell, or nil if the column does not exist.
+func (r DatasetRow) Cell(column string) any {
+	var value any
+	_ = r.DecodeCell(column, &value)
+	return value
+}
+
+// DecodeCell decodes the value of a cell into v, e.g. an ImageCell for an Image column.
+func (r DatasetRow) DecodeCell(column string, v any) error {
+	raw, ok := r.Row[column]
+	if !ok {
+		return fmt.Errorf("column %q not found", column)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Decode decodes the whole row into v, typically a struct tagged with column names.
+func (r DatasetRow) Decode(v any) error {
+	data, err := json.Marshal(r.Row)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ImageCell is the value of an Image cell.
+type ImageCell struct {
+	Src    string `json:"src"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+}
+
+// AudioCell is one of the sources of an Audio cell.
+type AudioCell struct {
+	Src  string `json:"src"`
+	Type string `json:"type"`
+}
+
+type DatasetSize struct {
+	Size struct {
+		Dataset DatasetSizeEntry   `json:"dataset"`
+		Configs []DatasetSizeEntry `json:"configs"`
+		Splits  []DatasetSizeEntry `json:"splits"`
+	} `json:"size"`
+	Pending []DatasetSplit        `json:"pending"`
+	Failed  []DatasetSplitFailure `json:"failed"`
+	Partial bool                  `json:"partial"`
+}
+
+type DatasetSizeEntry struct {
+	Dataset               string `json:"dataset"`
+	Config                string `json:"config,omitempty"`
+	Split                 string `json:"split,omitempty"`
+	NumBytesOriginalFiles int64  `json:"num_bytes_original_files"`
+	NumBytesParquetFiles  int64  `json:"num_bytes_parquet_files"`
+	NumBytesMemory        int64  `json:"num_bytes_memory"`
+	NumRows               int64  `json:"num_rows"`
+	NumColumns            int64  `json:"num_columns,omitempty"`
+	EstimatedNumRows      *int64 `json:"estimated_num_rows,omitempty"`
+}
+
+type DatasetStatistics struct {
+	NumExamples int64                     `json:"num_examples"`
+	Statistics  []DatasetColumnStatistics `json:"statistics"`
+	Partial     bool                      `json:"partial"`
+}
+
+type DatasetColumnStatistics struct {
+	ColumnName       string           `json:"column_name"`
+	ColumnType       string           `json:"column_type"`
+	ColumnStatistics ColumnStatistics `json:"column_statistics"`
+}
+
+// ColumnStatistics holds the statistics of a column. Which fields are set depends on the column type.
+type ColumnStatistics struct {
+	NanCount      int64            `json:"nan_count"`
+	NanProportion float64          `json:"nan_proportion"`
+	Min           *float64         `json:"min,omitempty"`
+	Max           *float64         `json:"max,omitempty"`
+	Mean          *float64         `json:"mean,omitempty"`
+	Median        *float64         `json:"median,omitempty"`
+	Std           *float64         `json:"std,omitempty"`
+	Histogram     *Histogram       `json:"histogram,omitempty"`
+	NoLabelCount  *int64           `json:"no_label_count,omitempty"`
+	NUnique       *int64           `json:"n_unique,omitempty"`
+	Frequencies   map[string]int64 `json:"frequencies,omitempty"`
+}
+
+type Histogram struct {
+	Hist     []int64   `json:"hist"`
+	BinEdges []float64 `json:"bin_edges"`
+}
+
+type DatasetParquetFiles struct {
+	ParquetFiles []DatasetParquetFile  `json:"parquet_files"`
+	Pending      []DatasetSplit        `json:"pending"`
+	Failed       []DatasetSplitFailure `json:"failed"`
+	Partial      bool                  `json:"partial"`
+}
+
+type DatasetParquetFile struct {
+	Dataset  string `json:"dataset"`
+	Config   string `json:"config"`
+	Split    string `json:"split"`
+	URL      string `json:"url"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
@@ -0,0 +1,245 @@
+package huggo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFeatureType_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"text": {"dtype": "string", "_type": "Value"},
+		"label": {"names": ["neg", "pos"], "_type": "ClassLabel"},
+		"tokens": {"feature": {"dtype": "string", "_type": "Value"}, "length": -1, "_type": "Sequence"},
+		"answers": {"text": [{"dtype": "string", "_type": "Value"}], "answer_start": {"dtype": "int32", "_type": "Value"}}
+	}`
+	var features map[string]*FeatureType
+	if err := json.Unmarshal([]byte(data), &features); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := features["text"]; got.Type != FeatureTypeValue || got.Dtype != "string" {
+		t.Errorf("Unexpected text feature: %+v", got)
+	}
+	if got := features["label"]; got.Type != FeatureTypeClassLabel || len(got.Names) != 2 {
+		t.Errorf("Unexpected label feature: %+v", got)
+	}
+	if got := features["tokens"]; got.Type != FeatureTypeSequence || got.Feature.Dtype != "string" || got.Length != -1 {
+		t.Errorf("Unexpected tokens feature: %+v", got)
+	}
+	answers := features["answers"]
+	if answers.Type != FeatureTypeDict || len(answers.Fields) != 2 {
+		t.Fatalf("Unexpected answers feature: %+v", answers)
+	}
+	if got := answers.Fields["text"]; got.Type != FeatureTypeList || got.Feature.Dtype != "string" {
+		t.Errorf("Unexpected answers.text feature: %+v", got)
+	}
+	if got := answers.Fields["answer_start"]; got.Dtype != "int32" {
+		t.Errorf("Unexpected answers.answer_start feature: %+v", got)
+	}
+}
+
+func TestDatasetViewer_IterRows(t *testing.T) {
+	const total = 250
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rows" {
+			http.NotFound(w, r)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("length"))
+		page := DatasetRowsPage{NumRowsTotal: total, NumRowsPerPage: int64(length)}
+		for i := offset; i < min(offset+length, total); i++ {
+			row := map[string]json.RawMessage{"idx": json.RawMessage(fmt.Sprint(i))}
+			page.Rows = append(page.Rows, DatasetRow{RowIdx: int64(i), Row: row})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithDatasetsServerURL(server.URL))
+	viewer := NewDatasetViewer(client)
+
+	count := 0
+	for row, err := range viewer.IterRows(context.Background(), "user/dataset", "default", "train", 0) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var idx int
+		if err := row.DecodeCell("idx", &idx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if idx != count {
+			t.Errorf("Expected row %d, got %d", count, idx)
+		}
+		count++
+	}
+	if count != total {
+		t.Errorf("Expected %d rows, got %d", total, count)
+	}
+}
+
+func TestDatasetViewer_GetSplits_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithDatasetsServerURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewDatasetViewer(client).GetSplits(ctx, "user/dataset")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled error, got %v", err)
+	}
+}
+
+func TestDatasetViewer_Requests(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		call      func(v *DatasetViewer) (any, error)
+		wantPath  string
+		wantQuery url.Values
+		body      string
+		check     func(t *testing.T, got any)
+	}{
+		{
+			name: "search",
+			call: func(v *DatasetViewer) (any, error) {
+				return v.SearchRows(ctx, "user/dataset", "default", "train", "hello world", 10, 20)
+			},
+			wantPath: "/search",
+			wantQuery: url.Values{
+				"dataset": {"user/dataset"}, "config": {"default"}, "split": {"train"},
+				"query": {"hello world"}, "offset": {"10"}, "length": {"20"},
+			},
+			body: `{"rows": [{"row_idx": 12, "row": {"text": "hello world"}}], "num_rows_total": 1}`,
+			check: func(t *testing.T, got any) {
+				page := got.(*DatasetRowsPage)
+				if len(page.Rows) != 1 || page.Rows[0].RowIdx != 12 || page.NumRowsTotal != 1 {
+					t.Errorf("Unexpected page %+v", page)
+				}
+			},
+		},
+		{
+			name: "filter",
+			call: func(v *DatasetViewer) (any, error) {
+				filter := DatasetFilter{
+					Where:   Column("label").Eq(1).And(Column("text").Like("%good%")),
+					OrderBy: []OrderBy{{Column: "score", Desc: true}, {Column: "text"}},
+				}
+				return v.FilterRows(ctx, "user/dataset", "default", "train", filter, 0, 100)
+			},
+			wantPath: "/filter",
+			wantQuery: url.Values{
+				"dataset": {"user/dataset"}, "config": {"default"}, "split": {"train"},
+				"where":   {`("label" = 1) AND ("text" LIKE '%good%')`},
+				"orderby": {`"score" DESC, "text" ASC`},
+				"offset":  {"0"}, "length": {"100"},
+			},
+			body: `{"rows": [], "num_rows_total": 0}`,
+			check: func(t *testing.T, got any) {
+				if page := got.(*DatasetRowsPage); len(page.Rows) != 0 {
+					t.Errorf("Unexpected page %+v", page)
+				}
+			},
+		},
+		{
+			name: "size",
+			call: func(v *DatasetViewer) (any, error) {
+				return v.GetSize(ctx, "user/dataset")
+			},
+			wantPath:  "/size",
+			wantQuery: url.Values{"dataset": {"user/dataset"}},
+			body:      `{"size": {"dataset": {"dataset": "user/dataset", "num_rows": 30}, "splits": [{"dataset": "user/dataset", "config": "default", "split": "train", "num_rows": 20}]}, "partial": false}`,
+			check: func(t *testing.T, got any) {
+				size := got.(*DatasetSize)
+				if size.Size.Dataset.NumRows != 30 || len(size.Size.Splits) != 1 || size.Size.Splits[0].Split != "train" {
+					t.Errorf("Unexpected size %+v", size)
+				}
+			},
+		},
+		{
+			name: "statistics",
+			call: func(v *DatasetViewer) (any, error) {
+				return v.GetStatistics(ctx, "user/dataset", "default", "train")
+			},
+			wantPath:  "/statistics",
+			wantQuery: url.Values{"dataset": {"user/dataset"}, "config": {"default"}, "split": {"train"}},
+			body:      `{"num_examples": 20, "statistics": [{"column_name": "score", "column_type": "float", "column_statistics": {"nan_count": 0, "min": 0.5, "max": 2}}]}`,
+			check: func(t *testing.T, got any) {
+				statistics := got.(*DatasetStatistics)
+				if statistics.NumExamples != 20 || len(statistics.Statistics) != 1 {
+					t.Fatalf("Unexpected statistics %+v", statistics)
+				}
+				if got := statistics.Statistics[0].ColumnStatistics.Max; got == nil || *got != 2 {
+					t.Errorf("Unexpected max %v", got)
+				}
+			},
+		},
+		{
+			name: "parquet",
+			call: func(v *DatasetViewer) (any, error) {
+				return v.GetParquetFiles(ctx, "user/dataset")
+			},
+			wantPath:  "/parquet",
+			wantQuery: url.Values{"dataset": {"user/dataset"}},
+			body:      `{"parquet_files": [{"dataset": "user/dataset", "config": "default", "split": "train", "filename": "0000.parquet", "size": 1024}]}`,
+			check: func(t *testing.T, got any) {
+				files := got.(*DatasetParquetFiles)
+				if len(files.ParquetFiles) != 1 || files.ParquetFiles[0].Filename != "0000.parquet" || files.ParquetFiles[0].Size != 1024 {
+					t.Errorf("Unexpected parquet files %+v", files)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("Expected path %s, got %s", tt.wantPath, r.URL.Path)
+				}
+				if got := r.URL.Query(); !reflect.DeepEqual(got, tt.wantQuery) {
+					t.Errorf("Expected query %v, got %v", tt.wantQuery, got)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, _ := NewHttpClient("apiKey", WithDatasetsServerURL(server.URL))
+			got, err := tt.call(NewDatasetViewer(client))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestDatasetViewer_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "The dataset does not exist."}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithDatasetsServerURL(server.URL))
+	_, err := NewDatasetViewer(client).GetSize(context.Background(), "user/missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || !strings.Contains(apiErr.Body, "does not exist") {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+}
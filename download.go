@@ -0,0 +1,291 @@
+package huggo
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxWorkers is the number of files downloaded concurrently when none is specified.
+const DefaultMaxWorkers = 8
+
+// resolveURL returns the URL serving the content of a repository file at a revision.
+func (c *HttpClient) resolveURL(repoType RepoType, id string, revision string, filename string) string {
+	return fmt.Sprintf("%s/%s%s/resolve/%s/%s", c.endpoint(), repoType.urlPrefix(), id, url.PathEscape(revision), escapePath(filename))
+}
+
+// escapePath escapes every segment of a slash-separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// ProgressFunc is called as a download progresses with the number of bytes downloaded so far and the total size.
+type ProgressFunc func(downloaded int64, total int64)
+
+type downloadOptions struct {
+	progress    ProgressFunc
+	connections int
+	chunkSize   int64
+}
+
+// DownloadOption defines a function that can customize a download.
+type DownloadOption func(options *downloadOptions)
+
+// WithProgress returns a DownloadOption that reports the progress of the download to fn.
+func WithProgress(fn ProgressFunc) DownloadOption {
+	return func(options *downloadOptions) {
+		options.progress = fn
+	}
+}
+
+// WithConnections returns a DownloadOption that downloads files larger than the chunk size over n concurrent
+// connections, each fetching a range of the file. See WithChunkSize.
+func WithConnections(n int) DownloadOption {
+	return func(options *downloadOptions) {
+		options.connections = n
+	}
+}
+
+// WithChunkSize returns a DownloadOption that sets the size of the ranges of files downloaded over several
+// connections. It defaults to DefaultChunkSize.
+func WithChunkSize(size int64) DownloadOption {
+	return func(options *downloadOptions) {
+		options.chunkSize = size
+	}
+}
+
+// DownloadFile downloads a file of a repository at a revision into the dst directory, keeping its repository
+// path, and returns the local path of the file along with the commit the revision resolved to.
+// If dst is empty, the file is downloaded into the cache instead (see WithCacheDir).
+//
+// The file is streamed through a partial file next to its destination, so that an interrupted download is
+// resumed with a Range request by the next call. The content is verified against its sha256 for LFS files and
+// against its git blob hash otherwise. A file already present with the expected content is not downloaded again.
+// Large files can be downloaded over several connections with WithConnections.
+func (r *Repository) DownloadFile(ctx context.Context, repoType RepoType, id string, revision string, filename string, dst string, opts ...DownloadOption) (string, string, error) {
+	options := &downloadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	if dst == "" {
+		return r.downloadFileToCache(ctx, repoType, id, revision, filename, options)
+	}
+	metadata, err := r.GetFileMetadata(ctx, repoType, id, revision, filename)
+	if err != nil {
+		return "", "", err
+	}
+	p := filepath.Join(dst, filepath.FromSlash(filename))
+	digest := metadataDigest(metadata)
+	if fileMatches(p, digest) {
+		return p, metadata.CommitHash, nil
+	}
+	// The file is downloaded at the resolved commit so that it matches the metadata.
+	rawURL := r.httpClient.resolveURL(repoType, id, metadata.CommitHash, filename)
+	err = r.httpClient.downloadToFile(ctx, rawURL, p, digest, options)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download %s: %w", filename, err)
+	}
+	return p, metadata.CommitHash, nil
+}
+
+// fileDigest identifies the expected content of a repository file.
+type fileDigest struct {
+	Size int64
+	// Hash is the sha256 of LFS files, or the git blob hash of other files.
+	Hash string
+	LFS  bool
+}
+
+// newHash returns the hash computing the digest of a file.
+func (d fileDigest) newHash() hash.Hash {
+	if d.LFS {
+		return sha256.New()
+	}
+	return newGitBlobHash(d.Size)
+}
+
+// treeDigest returns the digest of a file listed in a repository tree.
+func treeDigest(size int64, blobID string, lfs *LFSInfo) fileDigest {
+	if lfs != nil {
+		return fileDigest{Size: lfs.Size, Hash: lfs.SHA256, LFS: true}
+	}
+	return fileDigest{Size: size, Hash: blobID}
+}
+
+// metadataDigest returns the digest of a file described by its metadata.
+func metadataDigest(metadata *FileMetadata) fileDigest {
+	return fileDigest{Size: metadata.Size, Hash: metadata.ETag, LFS: metadata.LFS}
+}
+
+// downloadToFile streams the content at rawURL to dst and verifies it against digest.
+// The content is written to a partial file next to dst, which is resumed with a Range request if it exists.
+// Files larger than the chunk size are downloaded in chunks over several connections if options allow it.
+// A nil options downloads the file over a single connection without reporting progress.
+func (c *HttpClient) downloadToFile(ctx context.Context, rawURL string, dst string, digest fileDigest, options *downloadOptions) error {
+	if options == nil {
+		options = &downloadOptions{}
+	}
+	progress := options.progress
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	partial := dst + ".incomplete"
+	if filepath.Base(dst) != digest.Hash {
+		// The digest is part of the name so that a partial file is never resumed with different content.
+		partial = dst + "." + digest.Hash + ".incomplete"
+	}
+	f, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if options.connections > 1 && digest.Size > options.chunkSizeOrDefault() {
+		err := c.downloadChunks(ctx, rawURL, f, digest.Size, options)
+		if err == nil {
+			return commitPartialFile(f, partial, dst, digest)
+		}
+		// The preallocated file holds gaps where chunks were not downloaded, so it cannot be resumed by a single
+		// connection and is emptied.
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if !errors.Is(err, errRangeNotSupported) {
+			return err
+		}
+		// The server does not serve ranges, so the file is downloaded over a single connection instead.
+	}
+
+	// The content already downloaded is measured first so that the download resumes where it stopped.
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if offset > digest.Size {
+		offset = 0
+	}
+	if offset < digest.Size {
+		if _, err := c.downloadRange(ctx, rawURL, f, offset, digest.Size, progress); err != nil {
+			return err
+		}
+	}
+	return commitPartialFile(f, partial, dst, digest)
+}
+
+// commitPartialFile verifies the content of the partial file f against digest, and renames it to dst.
+// The partial file is removed if its content does not match, so that the next download starts from scratch.
+func commitPartialFile(f *os.File, partial string, dst string, digest fileDigest) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	h := digest.newHash()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != digest.Hash || size != digest.Size {
+		f.Close()
+		os.Remove(partial)
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", dst, digest.Hash, got)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partial, dst)
+}
+
+// downloadRange appends the content at rawURL from offset to f, and returns the final size of f.
+// If the server does not honor the range, f is truncated and the whole content is downloaded.
+func (c *HttpClient) downloadRange(ctx context.Context, rawURL string, f *os.File, offset int64, total int64, progress ProgressFunc) (int64, error) {
+	req, err := c.newURLRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+	default:
+		return 0, newAPIError(resp)
+	}
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	var w io.Writer = f
+	if progress != nil {
+		progress(offset, total)
+		w = &progressWriter{w: f, downloaded: offset, total: total, progress: progress}
+	}
+	n, err := io.Copy(w, resp.Body)
+	return offset + n, err
+}
+
+// progressWriter reports the number of bytes written through it.
+type progressWriter struct {
+	w          io.Writer
+	downloaded int64
+	total      int64
+	progress   ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.downloaded += int64(n)
+	w.progress(w.downloaded, w.total)
+	return n, err
+}
+
+// fileMatches reports whether the local file at p has the expected content.
+func fileMatches(p string, digest fileDigest) bool {
+	f, err := os.Open(p)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() != digest.Size || digest.Hash == "" {
+		return false
+	}
+	h := digest.newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return false
+	}
+	return hex.EncodeToString(h.Sum(nil)) == digest.Hash
+}
+
+// newGitBlobHash returns a hash computing the git blob hash of a content of the given size.
+func newGitBlobHash(size int64) hash.Hash {
+	h := sha1.New()
+	h.Write([]byte("blob " + strconv.FormatInt(size, 10) + "\x00"))
+	return h
+}
@@ -0,0 +1,75 @@
+package huggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/roushou/huggo/cache"
+)
+
+// downloadFileToCache downloads a repository file into the cache and returns the path of the file in the
+// snapshot of the commit the revision resolved to.
+// Files of commits are served from the cache without network access, and files of other revisions are served
+// from the cache when the Hub cannot be reached.
+func (r *Repository) downloadFileToCache(ctx context.Context, repoType RepoType, id string, revision string, filename string, options *downloadOptions) (string, string, error) {
+	c := r.httpClient.cache
+	cacheType := string(repoType)
+	if cache.IsCommitHash(revision) {
+		if p, commit, ok := c.Lookup(cacheType, id, revision, filename); ok {
+			return p, commit, nil
+		}
+	}
+
+	metadata, err := r.GetFileMetadata(ctx, repoType, id, revision, filename)
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) && ctx.Err() == nil {
+		if p, commit, ok := c.Lookup(cacheType, id, revision, filename); ok {
+			return p, commit, nil
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if err := c.WriteRef(cacheType, id, revision, metadata.CommitHash); err != nil {
+		return "", "", fmt.Errorf("failed to update cache ref: %w", err)
+	}
+	p, err := r.downloadBlobToCache(ctx, repoType, id, metadata.CommitHash, filename, metadataDigest(metadata), options)
+	if err != nil {
+		return "", "", err
+	}
+	return p, metadata.CommitHash, nil
+}
+
+// downloadBlobToCache downloads a file of a commit into the blob identified by its digest, unless it is cached
+// already, and links it into the snapshot of the commit. It returns the path of the file in the snapshot.
+func (r *Repository) downloadBlobToCache(ctx context.Context, repoType RepoType, id string, commit string, filename string, digest fileDigest, options *downloadOptions) (string, error) {
+	c := r.httpClient.cache
+	cacheType := string(repoType)
+	snapshot := c.SnapshotPath(cacheType, id, commit, filename)
+	if _, err := os.Stat(snapshot); err == nil {
+		return snapshot, nil
+	}
+
+	// Blobs are shared between snapshots and processes, so they are written under a lock.
+	unlock, err := c.Lock(ctx, cacheType, id, digest.Hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock cache: %w", err)
+	}
+	defer unlock()
+
+	blob := c.BlobPath(cacheType, id, digest.Hash)
+	if _, err := os.Stat(blob); err != nil {
+		rawURL := r.httpClient.resolveURL(repoType, id, commit, filename)
+		err = r.httpClient.downloadToFile(ctx, rawURL, blob, digest, options)
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", filename, err)
+		}
+	}
+	p, err := c.LinkSnapshot(cacheType, id, commit, filename, digest.Hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to link %s into snapshot: %w", filename, err)
+	}
+	return p, nil
+}
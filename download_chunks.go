@@ -0,0 +1,153 @@
+package huggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// DefaultChunkSize is the size of the ranges of files downloaded over several connections when none is specified.
+const DefaultChunkSize = 64 << 20
+
+// errRangeNotSupported is returned when a server ignores Range requests.
+var errRangeNotSupported = errors.New("range requests not supported")
+
+func (o *downloadOptions) chunkSizeOrDefault() int64 {
+	if o.chunkSize > 0 {
+		return o.chunkSize
+	}
+	return DefaultChunkSize
+}
+
+// downloadChunks downloads the content at rawURL into f, which is preallocated to size, by fetching chunks of it
+// concurrently over the configured number of connections and writing each chunk at its offset.
+// Interrupted chunks are resumed where they stopped, up to maxResumeRetries times in a row.
+func (c *HttpClient) downloadChunks(ctx context.Context, rawURL string, f *os.File, size int64, options *downloadOptions) error {
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunkSize := options.chunkSizeOrDefault()
+	chunks := make(chan int64)
+	go func() {
+		defer close(chunks)
+		for start := int64(0); start < size; start += chunkSize {
+			select {
+			case chunks <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	progress := &chunkProgress{total: size, progress: options.progress}
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for range options.connections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := min(start+chunkSize, size)
+				if err := c.downloadChunk(ctx, rawURL, f, start, end, progress); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// downloadChunk downloads the bytes from start to end (exclusive) of the content at rawURL into f, resuming the
+// transfer when it is interrupted.
+func (c *HttpClient) downloadChunk(ctx context.Context, rawURL string, f *os.File, start int64, end int64, progress *chunkProgress) error {
+	retries := 0
+	for start < end {
+		n, err := c.fetchChunk(ctx, rawURL, f, start, end, progress)
+		start += n
+		if err == nil {
+			continue
+		}
+		if n > 0 {
+			retries = 0
+		}
+		var apiErr *APIError
+		if errors.Is(err, errRangeNotSupported) || ctx.Err() != nil || retries >= maxResumeRetries ||
+			(errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError) {
+			return err
+		}
+		retries++
+	}
+	return nil
+}
+
+// fetchChunk writes the bytes from start to end (exclusive) of the content at rawURL into f with a single
+// Range request, and returns the number of bytes written.
+func (c *HttpClient) fetchChunk(ctx context.Context, rawURL string, f *os.File, start int64, end int64, progress *chunkProgress) (int64, error) {
+	req, err := c.newURLRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return 0, errRangeNotSupported
+	default:
+		return 0, newAPIError(resp)
+	}
+	w := &chunkWriter{w: io.NewOffsetWriter(f, start), progress: progress}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, end-start))
+	if err == nil && start+n < end {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// chunkProgress reports the progress of chunks downloaded concurrently.
+type chunkProgress struct {
+	mu         sync.Mutex
+	downloaded int64
+	total      int64
+	progress   ProgressFunc
+}
+
+func (p *chunkProgress) add(n int64) {
+	if p.progress == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downloaded += n
+	p.progress(p.downloaded, p.total)
+}
+
+type chunkWriter struct {
+	w        io.Writer
+	progress *chunkProgress
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.progress.add(int64(n))
+	return n, err
+}
@@ -0,0 +1,302 @@
+package huggo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRepository_DownloadFile(t *testing.T) {
+	content := strings.Repeat("safetensors", 1000)
+	sum := sha256.Sum256([]byte(content))
+	sha := hex.EncodeToString(sum[:])
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Repo-Commit", "abc123")
+		w.Header().Set("X-Linked-Etag", `"`+sha+`"`)
+		w.Header().Set("X-Linked-Size", "11000")
+		w.Header().Set("ETag", `"pointer"`)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if r.URL.Path != "/user/model/resolve/abc123/weights/model.safetensors" {
+			http.NotFound(w, r)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "model.safetensors", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	dir := t.TempDir()
+
+	// A previous download was interrupted halfway.
+	dst := filepath.Join(dir, "weights", "model.safetensors")
+	_ = os.MkdirAll(filepath.Dir(dst), 0o755)
+	_ = os.WriteFile(dst+"."+sha+".incomplete", []byte(content[:5000]), 0o644)
+
+	var lastDownloaded, lastTotal int64
+	progress := func(downloaded int64, total int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	}
+	p, commit, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "weights/model.safetensors", dir, WithProgress(progress))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p != dst || commit != "abc123" {
+		t.Errorf("Unexpected path %s or commit %s", p, commit)
+	}
+	got, _ := os.ReadFile(p)
+	if string(got) != content {
+		t.Errorf("Unexpected content of %d bytes", len(got))
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=5000-" {
+		t.Errorf("Expected download to resume, got ranges %v", ranges)
+	}
+	if lastDownloaded != 11000 || lastTotal != 11000 {
+		t.Errorf("Unexpected progress %d/%d", lastDownloaded, lastTotal)
+	}
+	if _, err := os.Stat(dst + "." + sha + ".incomplete"); !os.IsNotExist(err) {
+		t.Errorf("Expected partial file to be removed")
+	}
+
+	if _, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "weights/model.safetensors", dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ranges) != 1 {
+		t.Errorf("Expected file already downloaded to be skipped")
+	}
+}
+
+func TestRepository_DownloadFile_Chunks(t *testing.T) {
+	content := strings.Repeat("safetensors", 1000)
+	sum := sha256.Sum256([]byte(content))
+	sha := hex.EncodeToString(sum[:])
+
+	var mu sync.Mutex
+	var ranges []string
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Repo-Commit", "abc123")
+		w.Header().Set("X-Linked-Etag", `"`+sha+`"`)
+		w.Header().Set("X-Linked-Size", "11000")
+		if r.Method == http.MethodHead {
+			return
+		}
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		// The first transfer of the second chunk is interrupted halfway.
+		fail := !failed && r.Header.Get("Range") == "bytes=4096-8191"
+		failed = failed || fail
+		mu.Unlock()
+		if fail {
+			w.Header().Set("Content-Range", "bytes 4096-8191/11000")
+			w.Header().Set("Content-Length", "4096")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(content[4096:6000]))
+			return
+		}
+		http.ServeContent(w, r, "model.safetensors", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	dir := t.TempDir()
+
+	var lastDownloaded int64
+	progress := func(downloaded int64, total int64) {
+		lastDownloaded = downloaded
+	}
+	p, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "model.safetensors", dir,
+		WithConnections(3), WithChunkSize(4096), WithProgress(progress))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, _ := os.ReadFile(p)
+	if string(got) != content {
+		t.Errorf("Unexpected content of %d bytes", len(got))
+	}
+	sort.Strings(ranges)
+	want := []string{"bytes=0-4095", "bytes=4096-8191", "bytes=6000-8191", "bytes=8192-10999"}
+	if !slices.Equal(ranges, want) {
+		t.Errorf("Expected ranges %v, got %v", want, ranges)
+	}
+	if lastDownloaded != 11000 {
+		t.Errorf("Unexpected progress %d", lastDownloaded)
+	}
+}
+
+func TestRepository_DownloadFile_ChunksFailure(t *testing.T) {
+	content := strings.Repeat("safetensors", 1000)
+	sum := sha256.Sum256([]byte(content))
+	sha := hex.EncodeToString(sum[:])
+
+	var mu sync.Mutex
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Repo-Commit", "abc123")
+		w.Header().Set("X-Linked-Etag", `"`+sha+`"`)
+		w.Header().Set("X-Linked-Size", "11000")
+		if r.Method == http.MethodHead {
+			return
+		}
+		mu.Lock()
+		fail := failing && r.Header.Get("Range") == "bytes=4096-8191"
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.ServeContent(w, r, "model.safetensors", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	dir := t.TempDir()
+
+	_, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "model.safetensors", dir,
+		WithConnections(3), WithChunkSize(4096))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected forbidden chunk, got %v", err)
+	}
+
+	// The next download over a single connection does not resume the gaps left by the failed chunk.
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	p, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "model.safetensors", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(p); string(got) != content {
+		t.Errorf("Unexpected content of %d bytes", len(got))
+	}
+}
+
+func TestRepository_DownloadFile_ChunksRangeNotSupported(t *testing.T) {
+	content := strings.Repeat("safetensors", 1000)
+	sum := sha256.Sum256([]byte(content))
+	sha := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Repo-Commit", "abc123")
+		w.Header().Set("X-Linked-Etag", `"`+sha+`"`)
+		w.Header().Set("X-Linked-Size", "11000")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	p, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "model.safetensors", t.TempDir(),
+		WithConnections(4), WithChunkSize(1024))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(p); string(got) != content {
+		t.Errorf("Unexpected content of %d bytes", len(got))
+	}
+}
+
+func TestRepository_DownloadFile_ChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Repo-Commit", "abc123")
+		w.Header().Set("ETag", `"0000000000000000000000000000000000000000"`)
+		w.Header().Set("Content-Length", "7")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte("corrupt"))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	dir := t.TempDir()
+
+	_, _, err := repository.DownloadFile(context.Background(), RepoTypeModel, "user/model", "main", "config.json", dir)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected corrupt file not to be kept")
+	}
+}
+
+func TestRepository_DownloadFile_Cache(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	content := "{}"
+	h := newGitBlobHash(int64(len(content)))
+	h.Write([]byte(content))
+	etag := hex.EncodeToString(h.Sum(nil))
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Repo-Commit", commit)
+		w.Header().Set("ETag", `"`+etag+`"`)
+		w.Header().Set("Content-Length", "2")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+
+	cacheDir := t.TempDir()
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"), WithCacheDir(cacheDir))
+	repository := NewRepository(client)
+	ctx := context.Background()
+
+	p, gotCommit, err := repository.DownloadFile(ctx, RepoTypeDataset, "org/name", "main", "config.json", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := filepath.Join(cacheDir, "datasets--org--name", "snapshots", commit, "config.json")
+	if p != want || gotCommit != commit {
+		t.Errorf("Unexpected path %s or commit %s", p, gotCommit)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "datasets--org--name", "blobs", etag)); err != nil {
+		t.Errorf("Expected blob to be stored: %v", err)
+	}
+	ref, _ := os.ReadFile(filepath.Join(cacheDir, "datasets--org--name", "refs", "main"))
+	if string(ref) != commit {
+		t.Errorf("Unexpected ref %q", ref)
+	}
+
+	// Files of commits are served from the cache.
+	requests = 0
+	if _, _, err := repository.DownloadFile(ctx, RepoTypeDataset, "org/name", commit, "config.json", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request, got %d", requests)
+	}
+
+	// Files of branches are served from the cache when the Hub cannot be reached.
+	server.Close()
+	p, gotCommit, err = repository.DownloadFile(ctx, RepoTypeDataset, "org/name", "main", "config.json", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p != want || gotCommit != commit {
+		t.Errorf("Unexpected path %s or commit %s", p, gotCommit)
+	}
+}
@@ -0,0 +1,140 @@
+package huggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxRelativeRedirects is the number of redirects within the Hub, e.g. for renamed repositories, followed when
+// fetching file metadata.
+const maxRelativeRedirects = 5
+
+// FileMetadata describes a repository file as served by the resolve endpoint.
+type FileMetadata struct {
+	// CommitHash is the commit the revision resolved to.
+	CommitHash string
+	// ETag identifies the file content: the sha256 of LFS files, or the git blob hash of other files.
+	ETag string
+	// Size is the size of the file content in bytes.
+	Size int64
+	// Location is the URL the file content is downloaded from, which is a CDN URL for LFS files.
+	Location string
+	// LFS reports whether the file is stored with Git LFS.
+	LFS bool
+}
+
+// GetPathsInfo fetches information about the given files or directories of a repository at a revision.
+// Paths that do not exist are omitted. The last commit of every path is fetched when expand is set.
+func (r *Repository) GetPathsInfo(ctx context.Context, repoType RepoType, id string, revision string, paths []string, expand bool) ([]RepoTreeEntry, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	payload := struct {
+		Paths  []string `json:"paths"`
+		Expand bool     `json:"expand"`
+	}{Paths: paths, Expand: expand}
+	var entries []RepoTreeEntry
+	rawURL := fmt.Sprintf("%s/%s/%s/paths-info/%s", r.httpClient.baseURL, repoType.apiPath(), id, url.PathEscape(revision))
+	err := r.httpClient.postURL(ctx, rawURL, payload, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paths info: %w", err)
+	}
+	return entries, nil
+}
+
+// GetFileMetadata fetches the metadata of a repository file at a revision without downloading it.
+// The CDN redirect of LFS files is not followed, so that the metadata describes the file as stored on the Hub.
+func (r *Repository) GetFileMetadata(ctx context.Context, repoType RepoType, id string, revision string, filename string) (*FileMetadata, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	metadata, err := r.httpClient.headFile(ctx, r.httpClient.resolveURL(repoType, id, revision, filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// headFile sends a HEAD request to a resolve URL, following redirects within the Hub only.
+func (c *HttpClient) headFile(ctx context.Context, rawURL string) (*FileMetadata, error) {
+	client := *c.httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	for range maxRelativeRedirects {
+		req, err := c.newURLRequest(ctx, http.MethodHead, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Compressed responses would not report the actual size of the file.
+		req.Header.Set("Accept-Encoding", "identity")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		isRedirect := resp.StatusCode >= 300 && resp.StatusCode < 400
+		if isRedirect && strings.HasPrefix(location, "/") {
+			next, err := resp.Request.URL.Parse(location)
+			if err != nil {
+				return nil, err
+			}
+			rawURL = next.String()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK && !isRedirect {
+			return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status, Body: resp.Header.Get("X-Error-Message")}
+		}
+		return parseFileMetadata(resp.Header, rawURL)
+	}
+	return nil, errors.New("too many redirects")
+}
+
+// parseFileMetadata reads file metadata from the headers of a resolve response.
+func parseFileMetadata(header http.Header, rawURL string) (*FileMetadata, error) {
+	metadata := &FileMetadata{
+		CommitHash: header.Get("X-Repo-Commit"),
+		Location:   rawURL,
+	}
+	if location := header.Get("Location"); location != "" {
+		metadata.Location = location
+	}
+
+	etag, size := header.Get("ETag"), header.Get("Content-Length")
+	if linkedETag := header.Get("X-Linked-Etag"); linkedETag != "" {
+		etag = linkedETag
+		metadata.LFS = true
+	}
+	if linkedSize := header.Get("X-Linked-Size"); linkedSize != "" {
+		size = linkedSize
+	}
+	if metadata.CommitHash == "" {
+		return nil, errors.New("missing commit hash in response")
+	}
+	metadata.ETag = normalizeETag(etag)
+	if metadata.ETag == "" {
+		return nil, errors.New("missing ETag in response")
+	}
+	if size == "" {
+		return nil, errors.New("missing size in response")
+	}
+	var err error
+	metadata.Size, err = strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size in response: %w", err)
+	}
+	return metadata, nil
+}
+
+// normalizeETag strips the weak validator prefix and quotes of an ETag.
+func normalizeETag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
@@ -0,0 +1,100 @@
+package huggo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRepository_GetFileMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD request, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/old/model/resolve/main/config.json":
+			http.Redirect(w, r, "/user/model/resolve/main/config.json", http.StatusTemporaryRedirect)
+		case "/user/model/resolve/main/config.json":
+			w.Header().Set("X-Repo-Commit", "abc123")
+			w.Header().Set("ETag", `W/"blobhash"`)
+			w.Header().Set("Content-Length", "42")
+		case "/user/model/resolve/main/model.safetensors":
+			w.Header().Set("X-Repo-Commit", "abc123")
+			w.Header().Set("ETag", `"pointerhash"`)
+			w.Header().Set("X-Linked-Etag", `"sha256hash"`)
+			w.Header().Set("X-Linked-Size", "1000000")
+			w.Header().Set("Location", "https://cdn.example.com/model.safetensors")
+			w.WriteHeader(http.StatusFound)
+		case "/user/model/resolve/main/uncommitted.json":
+			w.Header().Set("ETag", `"blobhash"`)
+			w.Header().Set("Content-Length", "42")
+		default:
+			w.Header().Set("X-Error-Message", "Entry not found")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	ctx := context.Background()
+
+	metadata, err := repository.GetFileMetadata(ctx, RepoTypeModel, "old/model", "", "config.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := FileMetadata{CommitHash: "abc123", ETag: "blobhash", Size: 42, Location: server.URL + "/user/model/resolve/main/config.json"}
+	if *metadata != want {
+		t.Errorf("GetFileMetadata() = %+v, want %+v", *metadata, want)
+	}
+
+	metadata, err = repository.GetFileMetadata(ctx, RepoTypeModel, "user/model", "main", "model.safetensors")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = FileMetadata{CommitHash: "abc123", ETag: "sha256hash", Size: 1000000, Location: "https://cdn.example.com/model.safetensors", LFS: true}
+	if *metadata != want {
+		t.Errorf("GetFileMetadata() = %+v, want %+v", *metadata, want)
+	}
+
+	if _, err := repository.GetFileMetadata(ctx, RepoTypeModel, "user/model", "main", "missing.txt"); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+	if _, err := repository.GetFileMetadata(ctx, RepoTypeModel, "user/model", "main", "uncommitted.json"); err == nil {
+		t.Errorf("Expected error for missing commit hash")
+	}
+}
+
+func TestRepository_GetPathsInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/datasets/user/dataset/paths-info/main" {
+			http.NotFound(w, r)
+			return
+		}
+		var payload struct {
+			Paths []string `json:"paths"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		var entries []RepoTreeEntry
+		for _, p := range payload.Paths {
+			if p == "data" {
+				entries = append(entries, RepoTreeEntry{Type: RepoTreeEntryDirectory, Path: p})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(entries)
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	entries, err := repository.GetPathsInfo(context.Background(), RepoTypeDataset, "user/dataset", "", []string{"data", "missing"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
@@ -0,0 +1,576 @@
+package huggo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	// ggufMagic is "GGUF" read as a little-endian uint32.
+	ggufMagic = 0x46554747
+	// ggufDefaultAlignment is the alignment of tensor data when general.alignment is not set.
+	ggufDefaultAlignment = 32
+	// maxGGUFStringLength bounds the length of the strings read, to reject corrupted files early.
+	maxGGUFStringLength = 64 << 20
+	// maxGGUFDimensions is the maximum number of dimensions of a tensor.
+	maxGGUFDimensions = 4
+	// ggufReadBufferSize is the size of the reads of remote GGUF files, which headers typically span a few MiB.
+	ggufReadBufferSize = 1 << 20
+)
+
+// GGUF metadata value types.
+const (
+	ggufTypeUint8 uint32 = iota
+	ggufTypeInt8
+	ggufTypeUint16
+	ggufTypeInt16
+	ggufTypeUint32
+	ggufTypeInt32
+	ggufTypeFloat32
+	ggufTypeBool
+	ggufTypeString
+	ggufTypeArray
+	ggufTypeUint64
+	ggufTypeInt64
+	ggufTypeFloat64
+)
+
+// GetGGUFMetadata reads the metadata and tensor infos of a GGUF file of a repository at a revision, fetching
+// only its header with Range requests.
+func (r *Repository) GetGGUFMetadata(ctx context.Context, repoType RepoType, id string, revision string, filename string) (*GGUFFile, error) {
+	f, err := r.OpenRemoteFile(ctx, repoType, id, revision, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gguf, err := ReadGGUF(bufio.NewReaderSize(io.NewSectionReader(f, 0, f.Size()), ggufReadBufferSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GGUF header of %s: %w", filename, err)
+	}
+	return gguf, nil
+}
+
+// ReadGGUFFile reads the metadata and tensor infos of a local GGUF file.
+func ReadGGUFFile(name string) (*GGUFFile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGGUF(bufio.NewReader(f))
+}
+
+// ReadGGUF reads the header of a GGUF file: its metadata key/value pairs and its tensor infos.
+// Only versions 2 and 3 of the format are supported.
+func ReadGGUF(r io.Reader) (*GGUFFile, error) {
+	d := &ggufDecoder{r: r}
+	if magic := d.uint32(); d.err == nil && magic != ggufMagic {
+		return nil, errors.New("not a GGUF file")
+	}
+	gguf := &GGUFFile{Version: d.uint32()}
+	if d.err == nil && gguf.Version != 2 && gguf.Version != 3 {
+		return nil, fmt.Errorf("unsupported GGUF version %d", gguf.Version)
+	}
+	tensorCount, kvCount := d.uint64(), d.uint64()
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	gguf.Metadata = make(map[string]any, min(kvCount, 1024))
+	for i := uint64(0); i < kvCount && d.err == nil; i++ {
+		key := d.string()
+		value := d.value(d.uint32())
+		gguf.Metadata[key] = value
+	}
+	for i := uint64(0); i < tensorCount && d.err == nil; i++ {
+		tensor := GGUFTensorInfo{Name: d.string()}
+		dims := d.uint32()
+		if dims > maxGGUFDimensions {
+			return nil, fmt.Errorf("tensor %q has too many dimensions", tensor.Name)
+		}
+		for range dims {
+			tensor.Shape = append(tensor.Shape, d.uint64())
+		}
+		tensor.Type = GGMLType(d.uint32())
+		tensor.Offset = d.uint64()
+		gguf.Tensors = append(gguf.Tensors, tensor)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	alignment := int64(ggufDefaultAlignment)
+	if value, ok := gguf.uint("general.alignment"); ok && value > 0 {
+		alignment = int64(value)
+	}
+	gguf.TensorDataOffset = (d.n + alignment - 1) / alignment * alignment
+	return gguf, nil
+}
+
+// ggufDecoder reads little-endian GGUF values, recording the first error and the number of bytes read.
+type ggufDecoder struct {
+	r   io.Reader
+	n   int64
+	err error
+	buf [8]byte
+}
+
+func (d *ggufDecoder) read(size int) []byte {
+	if d.err != nil {
+		return d.buf[:size]
+	}
+	n, err := io.ReadFull(d.r, d.buf[:size])
+	d.n += int64(n)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	d.err = err
+	return d.buf[:size]
+}
+
+func (d *ggufDecoder) uint32() uint32 {
+	return binary.LittleEndian.Uint32(d.read(4))
+}
+
+func (d *ggufDecoder) uint64() uint64 {
+	return binary.LittleEndian.Uint64(d.read(8))
+}
+
+func (d *ggufDecoder) string() string {
+	length := d.uint64()
+	if d.err != nil {
+		return ""
+	}
+	if length > maxGGUFStringLength {
+		d.err = fmt.Errorf("invalid string length %d", length)
+		return ""
+	}
+	var b strings.Builder
+	n, err := io.CopyN(&b, d.r, int64(length))
+	d.n += n
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	d.err = err
+	return b.String()
+}
+
+// value reads a metadata value of the given type. Arrays of strings are returned as []string and other
+// arrays as []any.
+func (d *ggufDecoder) value(valueType uint32) any {
+	switch valueType {
+	case ggufTypeUint8:
+		return d.read(1)[0]
+	case ggufTypeInt8:
+		return int8(d.read(1)[0])
+	case ggufTypeUint16:
+		return binary.LittleEndian.Uint16(d.read(2))
+	case ggufTypeInt16:
+		return int16(binary.LittleEndian.Uint16(d.read(2)))
+	case ggufTypeUint32:
+		return d.uint32()
+	case ggufTypeInt32:
+		return int32(d.uint32())
+	case ggufTypeFloat32:
+		return math.Float32frombits(d.uint32())
+	case ggufTypeBool:
+		return d.read(1)[0] != 0
+	case ggufTypeString:
+		return d.string()
+	case ggufTypeUint64:
+		return d.uint64()
+	case ggufTypeInt64:
+		return int64(d.uint64())
+	case ggufTypeFloat64:
+		return math.Float64frombits(d.uint64())
+	case ggufTypeArray:
+		elemType, length := d.uint32(), d.uint64()
+		// The capacity is bounded so that a corrupted length fails on read rather than on allocation.
+		if elemType == ggufTypeString {
+			values := make([]string, 0, min(length, 1<<16))
+			for i := uint64(0); i < length && d.err == nil; i++ {
+				values = append(values, d.string())
+			}
+			return values
+		}
+		values := make([]any, 0, min(length, 1<<16))
+		for i := uint64(0); i < length && d.err == nil; i++ {
+			values = append(values, d.value(elemType))
+		}
+		return values
+	}
+	if d.err == nil {
+		d.err = fmt.Errorf("unsupported metadata value type %d", valueType)
+	}
+	return nil
+}
+
+// GetGGUFSummary reads the header of every GGUF file of a repository at a revision, and summarizes the
+// architecture and quantization of each.
+func (r *Repository) GetGGUFSummary(ctx context.Context, repoType RepoType, id string, revision string) ([]GGUFFileSummary, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	commit, err := r.getRevisionCommit(ctx, repoType, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	files, err := r.listRepoFiles(ctx, repoType, id, commit)
+	if err != nil {
+		return nil, err
+	}
+	var summaries []GGUFFileSummary
+	for _, file := range files {
+		if strings.EqualFold(path.Ext(file.Path), ".gguf") {
+			summaries = append(summaries, GGUFFileSummary{Path: file.Path, Size: file.Size})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make([]error, len(summaries))
+	sem := make(chan struct{}, DefaultMaxWorkers)
+	var wg sync.WaitGroup
+	for i := range summaries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			gguf, err := r.GetGGUFMetadata(ctx, repoType, id, commit, summaries[i].Path)
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			summary := &summaries[i]
+			summary.Architecture = gguf.Architecture()
+			summary.QuantType = gguf.QuantType()
+			summary.ParameterCount = gguf.ParameterCount()
+			summary.TensorTypes = gguf.TensorTypes()
+		}()
+	}
+	wg.Wait()
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// GGUFFile is the header of a GGUF file.
+type GGUFFile struct {
+	Version uint32
+	// Metadata holds the metadata values by key, typed after their GGUF type.
+	Metadata map[string]any
+	Tensors  []GGUFTensorInfo
+	// TensorDataOffset is the offset of the tensor data in the file.
+	TensorDataOffset int64
+}
+
+// StringValue returns a metadata string value.
+func (f *GGUFFile) StringValue(key string) string {
+	value, _ := f.Metadata[key].(string)
+	return value
+}
+
+// UintValue returns a metadata integer value of any integer type.
+func (f *GGUFFile) UintValue(key string) uint64 {
+	value, _ := f.uint(key)
+	return value
+}
+
+func (f *GGUFFile) uint(key string) (uint64, bool) {
+	switch value := f.Metadata[key].(type) {
+	case uint8:
+		return uint64(value), true
+	case uint16:
+		return uint64(value), true
+	case uint32:
+		return uint64(value), true
+	case uint64:
+		return value, true
+	case int8:
+		return uint64(max(value, 0)), true
+	case int16:
+		return uint64(max(value, 0)), true
+	case int32:
+		return uint64(max(value, 0)), true
+	case int64:
+		return uint64(max(value, 0)), true
+	}
+	return 0, false
+}
+
+// Architecture returns the architecture of the model, e.g. "llama".
+func (f *GGUFFile) Architecture() string {
+	return f.StringValue("general.architecture")
+}
+
+// Name returns the name of the model.
+func (f *GGUFFile) Name() string {
+	return f.StringValue("general.name")
+}
+
+// ArchitectureUint returns a metadata integer value of the architecture of the model, e.g. "context_length"
+// for "llama.context_length".
+func (f *GGUFFile) ArchitectureUint(key string) uint64 {
+	return f.UintValue(f.Architecture() + "." + key)
+}
+
+// ContextLength returns the context length the model was trained with.
+func (f *GGUFFile) ContextLength() uint64 {
+	return f.ArchitectureUint("context_length")
+}
+
+// FileType returns the type most tensors of the file are quantized to.
+func (f *GGUFFile) FileType() (GGUFFileType, bool) {
+	value, ok := f.uint("general.file_type")
+	return GGUFFileType(value), ok
+}
+
+// QuantType returns the name of the quantization of the file, e.g. "Q4_K_M". When the file does not declare
+// it, the type of the tensors holding most parameters is returned.
+func (f *GGUFFile) QuantType() string {
+	if fileType, ok := f.FileType(); ok {
+		return fileType.String()
+	}
+	var quantType GGMLType
+	var most int64
+	for tensorType, count := range f.TensorTypes() {
+		if count > most {
+			quantType, most = tensorType, count
+		}
+	}
+	return quantType.String()
+}
+
+// TokenizerModel returns the kind of tokenizer of the model, e.g. "gpt2" or "llama".
+func (f *GGUFFile) TokenizerModel() string {
+	return f.StringValue("tokenizer.ggml.model")
+}
+
+// VocabSize returns the number of tokens of the tokenizer of the model.
+func (f *GGUFFile) VocabSize() int {
+	tokens, _ := f.Metadata["tokenizer.ggml.tokens"].([]string)
+	return len(tokens)
+}
+
+// ChatTemplate returns the chat template of the model, if any.
+func (f *GGUFFile) ChatTemplate() string {
+	return f.StringValue("tokenizer.chat_template")
+}
+
+// ParameterCount returns the number of parameters of the tensors of the file.
+func (f *GGUFFile) ParameterCount() int64 {
+	var count int64
+	for _, tensor := range f.Tensors {
+		count += tensor.ParameterCount()
+	}
+	return count
+}
+
+// TensorTypes returns the number of parameters of the file by tensor type.
+func (f *GGUFFile) TensorTypes() map[GGMLType]int64 {
+	types := make(map[GGMLType]int64)
+	for _, tensor := range f.Tensors {
+		types[tensor.Type] += tensor.ParameterCount()
+	}
+	return types
+}
+
+// TensorDataSize returns the size of the tensor data of the file in bytes.
+func (f *GGUFFile) TensorDataSize() int64 {
+	var size int64
+	for _, tensor := range f.Tensors {
+		size += tensor.Size()
+	}
+	return size
+}
+
+type GGUFTensorInfo struct {
+	Name  string
+	Shape []uint64
+	Type  GGMLType
+	// Offset is the offset of the tensor data, relative to the TensorDataOffset of the file.
+	Offset uint64
+}
+
+// ParameterCount returns the number of elements of the tensor.
+func (t GGUFTensorInfo) ParameterCount() int64 {
+	count := int64(1)
+	for _, dim := range t.Shape {
+		count *= int64(dim)
+	}
+	return count
+}
+
+// Size returns the size of the tensor data in bytes.
+func (t GGUFTensorInfo) Size() int64 {
+	return t.Type.Size(t.ParameterCount())
+}
+
+// GGUFFileSummary summarizes a GGUF file of a repository.
+type GGUFFileSummary struct {
+	Path           string
+	Size           int64
+	Architecture   string
+	QuantType      string
+	ParameterCount int64
+	TensorTypes    map[GGMLType]int64
+}
+
+// GGMLType is the type of the elements of a tensor.
+type GGMLType uint32
+
+const (
+	GGMLTypeF32     GGMLType = 0
+	GGMLTypeF16     GGMLType = 1
+	GGMLTypeQ4_0    GGMLType = 2
+	GGMLTypeQ4_1    GGMLType = 3
+	GGMLTypeQ5_0    GGMLType = 6
+	GGMLTypeQ5_1    GGMLType = 7
+	GGMLTypeQ8_0    GGMLType = 8
+	GGMLTypeQ8_1    GGMLType = 9
+	GGMLTypeQ2_K    GGMLType = 10
+	GGMLTypeQ3_K    GGMLType = 11
+	GGMLTypeQ4_K    GGMLType = 12
+	GGMLTypeQ5_K    GGMLType = 13
+	GGMLTypeQ6_K    GGMLType = 14
+	GGMLTypeQ8_K    GGMLType = 15
+	GGMLTypeIQ2_XXS GGMLType = 16
+	GGMLTypeIQ2_XS  GGMLType = 17
+	GGMLTypeIQ3_XXS GGMLType = 18
+	GGMLTypeIQ1_S   GGMLType = 19
+	GGMLTypeIQ4_NL  GGMLType = 20
+	GGMLTypeIQ3_S   GGMLType = 21
+	GGMLTypeIQ2_S   GGMLType = 22
+	GGMLTypeIQ4_XS  GGMLType = 23
+	GGMLTypeI8      GGMLType = 24
+	GGMLTypeI16     GGMLType = 25
+	GGMLTypeI32     GGMLType = 26
+	GGMLTypeI64     GGMLType = 27
+	GGMLTypeF64     GGMLType = 28
+	GGMLTypeIQ1_M   GGMLType = 29
+	GGMLTypeBF16    GGMLType = 30
+	GGMLTypeTQ1_0   GGMLType = 34
+	GGMLTypeTQ2_0   GGMLType = 35
+)
+
+// ggmlTypeTraits describes how the elements of a tensor type are stored: blocks of blockSize elements take
+// typeSize bytes.
+var ggmlTypeTraits = map[GGMLType]struct {
+	name      string
+	blockSize int64
+	typeSize  int64
+}{
+	GGMLTypeF32:     {"F32", 1, 4},
+	GGMLTypeF16:     {"F16", 1, 2},
+	GGMLTypeQ4_0:    {"Q4_0", 32, 18},
+	GGMLTypeQ4_1:    {"Q4_1", 32, 20},
+	GGMLTypeQ5_0:    {"Q5_0", 32, 22},
+	GGMLTypeQ5_1:    {"Q5_1", 32, 24},
+	GGMLTypeQ8_0:    {"Q8_0", 32, 34},
+	GGMLTypeQ8_1:    {"Q8_1", 32, 36},
+	GGMLTypeQ2_K:    {"Q2_K", 256, 84},
+	GGMLTypeQ3_K:    {"Q3_K", 256, 110},
+	GGMLTypeQ4_K:    {"Q4_K", 256, 144},
+	GGMLTypeQ5_K:    {"Q5_K", 256, 176},
+	GGMLTypeQ6_K:    {"Q6_K", 256, 210},
+	GGMLTypeQ8_K:    {"Q8_K", 256, 292},
+	GGMLTypeIQ2_XXS: {"IQ2_XXS", 256, 66},
+	GGMLTypeIQ2_XS:  {"IQ2_XS", 256, 74},
+	GGMLTypeIQ3_XXS: {"IQ3_XXS", 256, 98},
+	GGMLTypeIQ1_S:   {"IQ1_S", 256, 50},
+	GGMLTypeIQ4_NL:  {"IQ4_NL", 32, 18},
+	GGMLTypeIQ3_S:   {"IQ3_S", 256, 110},
+	GGMLTypeIQ2_S:   {"IQ2_S", 256, 82},
+	GGMLTypeIQ4_XS:  {"IQ4_XS", 256, 136},
+	GGMLTypeI8:      {"I8", 1, 1},
+	GGMLTypeI16:     {"I16", 1, 2},
+	GGMLTypeI32:     {"I32", 1, 4},
+	GGMLTypeI64:     {"I64", 1, 8},
+	GGMLTypeF64:     {"F64", 1, 8},
+	GGMLTypeIQ1_M:   {"IQ1_M", 256, 56},
+	GGMLTypeBF16:    {"BF16", 1, 2},
+	GGMLTypeTQ1_0:   {"TQ1_0", 256, 54},
+	GGMLTypeTQ2_0:   {"TQ2_0", 256, 66},
+}
+
+func (t GGMLType) String() string {
+	if traits, ok := ggmlTypeTraits[t]; ok {
+		return traits.name
+	}
+	return fmt.Sprintf("GGMLType(%d)", uint32(t))
+}
+
+// Size returns the size in bytes of the given number of elements of the type, or 0 if the type is unknown.
+func (t GGMLType) Size(elements int64) int64 {
+	traits, ok := ggmlTypeTraits[t]
+	if !ok {
+		return 0
+	}
+	return (elements + traits.blockSize - 1) / traits.blockSize * traits.typeSize
+}
+
+// BitsPerWeight returns the average number of bits an element of the type takes, or 0 if the type is unknown.
+func (t GGMLType) BitsPerWeight() float64 {
+	traits, ok := ggmlTypeTraits[t]
+	if !ok {
+		return 0
+	}
+	return float64(traits.typeSize*8) / float64(traits.blockSize)
+}
+
+// GGUFFileType is the quantization of a GGUF file as a whole, as declared by general.file_type.
+type GGUFFileType uint32
+
+var ggufFileTypeNames = map[GGUFFileType]string{
+	0:  "F32",
+	1:  "F16",
+	2:  "Q4_0",
+	3:  "Q4_1",
+	7:  "Q8_0",
+	8:  "Q5_0",
+	9:  "Q5_1",
+	10: "Q2_K",
+	11: "Q3_K_S",
+	12: "Q3_K_M",
+	13: "Q3_K_L",
+	14: "Q4_K_S",
+	15: "Q4_K_M",
+	16: "Q5_K_S",
+	17: "Q5_K_M",
+	18: "Q6_K",
+	19: "IQ2_XXS",
+	20: "IQ2_XS",
+	21: "Q2_K_S",
+	22: "IQ3_XS",
+	23: "IQ3_XXS",
+	24: "IQ1_S",
+	25: "IQ4_NL",
+	26: "IQ3_S",
+	27: "IQ3_M",
+	28: "IQ2_S",
+	29: "IQ2_M",
+	30: "IQ4_XS",
+	31: "IQ1_M",
+	32: "BF16",
+	36: "TQ1_0",
+	37: "TQ2_0",
+}
+
+func (t GGUFFileType) String() string {
+	if name, ok := ggufFileTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("GGUFFileType(%d)", uint32(t))
+}
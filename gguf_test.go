@@ -0,0 +1,167 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGGUFFile builds a version 3 GGUF header with the given metadata and a Q4_K and a F32 tensor.
+func newGGUFFile(metadata map[string]any) []byte {
+	var buf bytes.Buffer
+	write := func(v any) { _ = binary.Write(&buf, binary.LittleEndian, v) }
+	writeString := func(s string) {
+		write(uint64(len(s)))
+		buf.WriteString(s)
+	}
+	write(uint32(ggufMagic))
+	write(uint32(3))
+	write(uint64(2))
+	write(uint64(len(metadata)))
+	for key, value := range metadata {
+		writeString(key)
+		switch value := value.(type) {
+		case string:
+			write(ggufTypeString)
+			writeString(value)
+		case uint32:
+			write(ggufTypeUint32)
+			write(value)
+		case []string:
+			write(ggufTypeArray)
+			write(ggufTypeString)
+			write(uint64(len(value)))
+			for _, s := range value {
+				writeString(s)
+			}
+		case []int32:
+			write(ggufTypeArray)
+			write(ggufTypeInt32)
+			write(uint64(len(value)))
+			write(value)
+		}
+	}
+	writeString("blk.0.attn_q.weight")
+	write(uint32(2))
+	write([]uint64{4096, 4096})
+	write(uint32(GGMLTypeQ4_K))
+	write(uint64(0))
+	writeString("output_norm.weight")
+	write(uint32(1))
+	write(uint64(4096))
+	write(uint32(GGMLTypeF32))
+	write(uint64(GGMLTypeQ4_K.Size(4096 * 4096)))
+	return buf.Bytes()
+}
+
+func TestReadGGUFFile(t *testing.T) {
+	data := newGGUFFile(map[string]any{
+		"general.architecture":       "llama",
+		"general.name":               "Tiny",
+		"general.file_type":          uint32(15),
+		"llama.context_length":       uint32(8192),
+		"tokenizer.ggml.model":       "gpt2",
+		"tokenizer.ggml.tokens":      []string{"a", "b", "c"},
+		"tokenizer.ggml.token_type":  []int32{1, 1, 3},
+		"tokenizer.chat_template":    "{{ messages }}",
+		"llama.attention.head_count": uint32(32),
+	})
+	p := filepath.Join(t.TempDir(), "model.gguf")
+	_ = os.WriteFile(p, data, 0o644)
+
+	gguf, err := ReadGGUFFile(p)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gguf.Architecture() != "llama" || gguf.Name() != "Tiny" || gguf.ContextLength() != 8192 {
+		t.Errorf("Unexpected metadata %v", gguf.Metadata)
+	}
+	if gguf.QuantType() != "Q4_K_M" || gguf.TokenizerModel() != "gpt2" || gguf.VocabSize() != 3 || gguf.ChatTemplate() == "" {
+		t.Errorf("Unexpected metadata %v", gguf.Metadata)
+	}
+	if gguf.ArchitectureUint("attention.head_count") != 32 {
+		t.Errorf("Unexpected head count %v", gguf.Metadata["llama.attention.head_count"])
+	}
+	if types, ok := gguf.Metadata["tokenizer.ggml.token_type"].([]any); !ok || len(types) != 3 || types[2] != int32(3) {
+		t.Errorf("Unexpected token types %v", gguf.Metadata["tokenizer.ggml.token_type"])
+	}
+	if len(gguf.Tensors) != 2 || gguf.ParameterCount() != 4096*4096+4096 {
+		t.Errorf("Unexpected tensors %+v", gguf.Tensors)
+	}
+	if want := int64(4096*4096/256*144 + 4096*4); gguf.TensorDataSize() != want {
+		t.Errorf("Expected tensor data size %d, got %d", want, gguf.TensorDataSize())
+	}
+	if gguf.TensorDataOffset%32 != 0 || gguf.TensorDataOffset < int64(len(data)) {
+		t.Errorf("Unexpected tensor data offset %d for a header of %d bytes", gguf.TensorDataOffset, len(data))
+	}
+}
+
+func TestReadGGUF_Invalid(t *testing.T) {
+	if _, err := ReadGGUF(strings.NewReader("GGML\x03\x00\x00\x00")); err == nil {
+		t.Errorf("Expected error for invalid magic")
+	}
+	data := newGGUFFile(map[string]any{"general.architecture": "llama"})
+	if _, err := ReadGGUF(bytes.NewReader(data[:len(data)-4])); err == nil {
+		t.Errorf("Expected error for truncated header")
+	}
+}
+
+func TestRepository_GetGGUFSummary(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	files := map[string][]byte{
+		"tiny-Q4_K_M.gguf": newGGUFFile(map[string]any{"general.architecture": "llama", "general.file_type": uint32(15)}),
+		// The quantization of files without general.file_type is derived from their tensors.
+		"tiny-Q4_K.gguf": newGGUFFile(map[string]any{"general.architecture": "llama"}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/models/user/model/revision/main", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"sha": commit})
+	})
+	mux.HandleFunc("/api/models/user/model/tree/"+commit, func(w http.ResponseWriter, r *http.Request) {
+		tree := []RepoTreeEntry{{Type: "file", Path: "README.md", Size: 10}}
+		for name, content := range files {
+			tree = append(tree, RepoTreeEntry{Type: "file", Path: name, Size: int64(len(content))})
+		}
+		_ = json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/user/model/resolve/"+commit+"/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/user/model/resolve/"+commit+"/")
+		content := files[name]
+		sum := sha256.Sum256(content)
+		w.Header().Set("X-Repo-Commit", commit)
+		w.Header().Set("X-Linked-Etag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("X-Linked-Size", fmt.Sprint(len(content)))
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	summaries, err := repository.GetGGUFSummary(context.Background(), RepoTypeModel, "user/model", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(summaries))
+	}
+	for _, summary := range summaries {
+		want := strings.TrimSuffix(strings.TrimPrefix(summary.Path, "tiny-"), ".gguf")
+		if summary.QuantType != want || summary.Architecture != "llama" || summary.TensorTypes[GGMLTypeQ4_K] != 4096*4096 {
+			t.Errorf("Unexpected summary %+v", summary)
+		}
+	}
+}
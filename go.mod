@@ -1,3 +1,5 @@
 module github.com/roushou/huggo
 
 go 1.23.4
+
+require github.com/klauspost/compress v1.18.2
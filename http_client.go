@@ -2,18 +2,27 @@ package huggo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+
+	"github.com/roushou/huggo/cache"
 )
 
-const DefaultAPIBaseURL = "https://huggingface.co/api"
+const (
+	DefaultAPIBaseURL        = "https://huggingface.co/api"
+	DefaultDatasetsServerURL = "https://datasets-server.huggingface.co"
+)
 
 type options struct {
-	apiKey  string
-	baseURL string
+	apiKey            string
+	baseURL           string
+	datasetsServerURL string
+	cacheDir          string
 }
 
 // Option defines a function that can customize the Client.
@@ -30,29 +39,58 @@ func WithAPIKey(apiKey string) Option {
 	}
 }
 
-// WithBaseURL returns an Option that sets the base URL for API requests.
+// WithBaseURL returns an Option that sets the base URL for API requests, e.g. "https://huggingface.co/api".
+// Trailing slashes are ignored.
 func WithBaseURL(baseURL string) Option {
 	return func(options *options) error {
 		if baseURL == "" {
 			return errors.New("base URL should not be empty")
 		}
-		options.baseURL = baseURL
+		options.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+// WithDatasetsServerURL returns an Option that sets the base URL for dataset viewer requests.
+// Trailing slashes are ignored.
+func WithDatasetsServerURL(datasetsServerURL string) Option {
+	return func(options *options) error {
+		if datasetsServerURL == "" {
+			return errors.New("datasets server URL should not be empty")
+		}
+		options.datasetsServerURL = strings.TrimRight(datasetsServerURL, "/")
+		return nil
+	}
+}
+
+// WithCacheDir returns an Option that sets the directory files are downloaded to when no destination is given.
+// It defaults to the cache directory of the Hugging Face libraries, see cache.Dir.
+func WithCacheDir(cacheDir string) Option {
+	return func(options *options) error {
+		if cacheDir == "" {
+			return errors.New("cache directory should not be empty")
+		}
+		options.cacheDir = cacheDir
 		return nil
 	}
 }
 
 // HttpClient represents a client for interacting with the HuggingFace API.
 type HttpClient struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	apiKey            string
+	baseURL           string
+	datasetsServerURL string
+	cache             *cache.Cache
+	httpClient        *http.Client
 }
 
 // NewHttpClient creates a new HTTP client with default settings and optional configurations.
 func NewHttpClient(apiKey string, opts ...Option) (*HttpClient, error) {
 	options := &options{
-		apiKey:  apiKey,
-		baseURL: DefaultAPIBaseURL,
+		apiKey:            apiKey,
+		baseURL:           DefaultAPIBaseURL,
+		datasetsServerURL: DefaultDatasetsServerURL,
+		cacheDir:          cache.Dir(),
 	}
 	for _, opt := range opts {
 		err := opt(options)
@@ -62,39 +100,75 @@ func NewHttpClient(apiKey string, opts ...Option) (*HttpClient, error) {
 	}
 
 	return &HttpClient{
-		apiKey:     options.apiKey,
-		baseURL:    options.baseURL,
-		httpClient: http.DefaultClient,
+		apiKey:            options.apiKey,
+		baseURL:           options.baseURL,
+		datasetsServerURL: options.datasetsServerURL,
+		cache:             cache.New(options.cacheDir),
+		httpClient:        http.DefaultClient,
 	}, nil
 }
 
 // newRequest constructs a new HTTP request.
 func (c *HttpClient) newRequest(method string, path string, body io.Reader) (*http.Request, error) {
-	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, body)
+	return c.newURLRequest(context.Background(), method, c.baseURL+path, body)
+}
+
+// newURLRequest constructs a new HTTP request to an absolute URL.
+func (c *HttpClient) newURLRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
 	return req, nil
 }
 
+// endpoint returns the root URL of the Hub, under which repository files are served.
+func (c *HttpClient) endpoint() string {
+	return strings.TrimSuffix(c.baseURL, "/api")
+}
+
 // doRequest sends an HTTP request and decodes the response into the provided interface.
 func (c *HttpClient) doRequest(req *http.Request, out interface{}) error {
+	_, err := c.doRequestHeader(req, out)
+	return err
+}
+
+// doRequestHeader sends an HTTP request, decodes the response into the provided interface and returns the response headers.
+func (c *HttpClient) doRequestHeader(req *http.Request, out interface{}) (http.Header, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("request failed (status: %s, body: %s)", resp.Status, string(body))
-
+		return nil, newAPIError(resp)
 	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	if out == nil {
+		return resp.Header, nil
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
 
+// APIError is returned when the API responds with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("request failed (status: %s, body: %s)", e.Status, e.Body)
+}
+
+// newAPIError reads the body of an unexpected response into an APIError.
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}
 }
 
 // Get sends a GET request.
@@ -106,6 +180,41 @@ func (c *HttpClient) Get(path string, out interface{}) error {
 	return c.doRequest(req, out)
 }
 
+// getURL sends a GET request to an absolute URL.
+func (c *HttpClient) getURL(ctx context.Context, url string, out interface{}) error {
+	req, err := c.newURLRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("GET request failed: %v", err)
+	}
+	return c.doRequest(req, out)
+}
+
+// getPage sends a GET request to an absolute URL and returns the URL of the next page, if any.
+func (c *HttpClient) getPage(ctx context.Context, url string, out interface{}) (string, error) {
+	req, err := c.newURLRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("GET request failed: %v", err)
+	}
+	header, err := c.doRequestHeader(req, out)
+	if err != nil {
+		return "", err
+	}
+	return nextPageURL(header), nil
+}
+
+// nextPageURL extracts the URL of the next page from the Link header of a paginated response.
+func nextPageURL(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	}
+	return ""
+}
+
 // Post sends a POST request.
 func (c *HttpClient) Post(path string, payload interface{}, out interface{}) error {
 	body, err := json.Marshal(payload)
@@ -119,6 +228,19 @@ func (c *HttpClient) Post(path string, payload interface{}, out interface{}) err
 	return c.doRequest(req, out)
 }
 
+// postURL sends a POST request to an absolute URL.
+func (c *HttpClient) postURL(ctx context.Context, url string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize body: %v", err)
+	}
+	req, err := c.newURLRequest(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	return c.doRequest(req, out)
+}
+
 // Put sends a PUT request.
 func (c *HttpClient) Put(path string, payload interface{}, out interface{}) error {
 	body, err := json.Marshal(payload)
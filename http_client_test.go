@@ -23,6 +23,29 @@ func TestNewHttpClient(t *testing.T) {
 	}
 }
 
+func TestHttpClient_endpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{name: "api", baseURL: "https://hf.co/api", want: "https://hf.co"},
+		{name: "trailing slash", baseURL: "https://hf.co/api/", want: "https://hf.co"},
+		{name: "mirror", baseURL: "https://mirror.example.com/hf/api//", want: "https://mirror.example.com/hf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewHttpClient("apiKey", WithBaseURL(tt.baseURL))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := client.endpoint(); got != tt.want {
+				t.Errorf("Expected endpoint %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	client, _ := NewHttpClient("apiKey")
 	req, err := client.newRequest(http.MethodGet, "/hello", nil)
@@ -39,3 +62,37 @@ func TestNewRequest(t *testing.T) {
 		t.Errorf("Expected 'Authorization' header to be set to 'Bearer apiKey', got %s", req.Header.Get("Authorization"))
 	}
 }
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "no link",
+			link: "",
+			want: "",
+		},
+		{
+			name: "next link",
+			link: `<https://huggingface.co/api/models?cursor=abc>; rel="next"`,
+			want: "https://huggingface.co/api/models?cursor=abc",
+		},
+		{
+			name: "several links",
+			link: `<https://huggingface.co/api/models?cursor=a>; rel="prev", <https://huggingface.co/api/models?cursor=b>; rel="next"`,
+			want: "https://huggingface.co/api/models?cursor=b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Link", tt.link)
+			if got := nextPageURL(header); got != tt.want {
+				t.Errorf("nextPageURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,27 @@
 package huggo
 
 type Hub struct {
-	Collection *Collection
-	Search     *Search
-	User       *User
+	Collection    *Collection
+	DatasetViewer *DatasetViewer
+	Papers        *Papers
+	Repository    *Repository
+	Search        *Search
+	User          *User
 }
 
 // NewHub creates a Hub client
-func NewHub(apiKey string) (*Hub, error) {
-	httpClient, err := NewHttpClient(apiKey)
+func NewHub(apiKey string, opts ...Option) (*Hub, error) {
+	httpClient, err := NewHttpClient(apiKey, opts...)
 	if err != nil {
 		return nil, err
 	}
 	hub := &Hub{
-		Collection: NewCollection(httpClient),
-		Search:     NewSearch(httpClient),
-		User:       NewUser(httpClient),
+		Collection:    NewCollection(httpClient),
+		DatasetViewer: NewDatasetViewer(httpClient),
+		Papers:        NewPapers(httpClient),
+		Repository:    NewRepository(httpClient),
+		Search:        NewSearch(httpClient),
+		User:          NewUser(httpClient),
 	}
 	return hub, nil
 }
@@ -0,0 +1,408 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// uploadBatchSize is the number of files sent per preupload and LFS batch request.
+	uploadBatchSize = 256
+	// uploadSampleSize is the number of leading bytes of a file the Hub inspects to decide how to upload it.
+	uploadSampleSize = 512
+	// maxUploadRetries is the number of times an upload request is retried after a network or server error.
+	maxUploadRetries = 3
+	// uploadRetryBackoff is the delay before the first retry of an upload request, doubled for every next retry.
+	uploadRetryBackoff = 250 * time.Millisecond
+	// lfsContentType is the media type of the requests and responses of the Git LFS API.
+	lfsContentType = "application/vnd.git-lfs+json"
+)
+
+// Upload modes of the files of a commit, as decided by the Hub.
+const (
+	uploadModeRegular = "regular"
+	uploadModeLFS     = "lfs"
+	uploadModeIgnored = "ignored"
+)
+
+// fileUpload is a file added by a commit, along with how it is uploaded.
+type fileUpload struct {
+	op     CommitOperationAdd
+	sha256 string
+	size   int64
+	sample []byte
+	mode   string
+}
+
+// uploadFiles hashes the files added by a commit, asks the Hub how each of them should be uploaded, and uploads
+// those that go to LFS storage. It returns the uploads by path in the repository.
+func (r *Repository) uploadFiles(ctx context.Context, repoType RepoType, id string, revision string, operations []CommitOperation, opts CommitOptions) (map[string]*fileUpload, error) {
+	var uploads []*fileUpload
+	for _, operation := range operations {
+		if op, ok := operation.(CommitOperationAdd); ok {
+			upload, err := newFileUpload(op)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", op.PathInRepo, err)
+			}
+			uploads = append(uploads, upload)
+		}
+	}
+	for batch := range slices.Chunk(uploads, uploadBatchSize) {
+		if err := r.preupload(ctx, repoType, id, revision, batch, opts.CreatePR); err != nil {
+			return nil, err
+		}
+	}
+
+	var lfsUploads []*fileUpload
+	for _, upload := range uploads {
+		if upload.mode == uploadModeLFS {
+			lfsUploads = append(lfsUploads, upload)
+		}
+	}
+	for batch := range slices.Chunk(lfsUploads, uploadBatchSize) {
+		if err := r.uploadLFSFiles(ctx, repoType, id, revision, batch, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	byPath := make(map[string]*fileUpload, len(uploads))
+	for _, upload := range uploads {
+		byPath[upload.op.PathInRepo] = upload
+	}
+	return byPath, nil
+}
+
+// newFileUpload reads the file added by op to compute its sha256 and sample.
+func newFileUpload(op CommitOperationAdd) (*fileUpload, error) {
+	content, size, err := op.open()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(content, 0, size)); err != nil {
+		return nil, err
+	}
+	sample := make([]byte, min(size, uploadSampleSize))
+	if _, err := content.ReadAt(sample, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return &fileUpload{op: op, sha256: hex.EncodeToString(h.Sum(nil)), size: size, sample: sample}, nil
+}
+
+// preupload asks the Hub whether each file should be sent inline with the commit or uploaded to LFS storage,
+// and whether it is ignored by the .gitignore of the repository.
+func (r *Repository) preupload(ctx context.Context, repoType RepoType, id string, revision string, uploads []*fileUpload, createPR bool) error {
+	type preuploadFile struct {
+		Path   string `json:"path"`
+		Sample string `json:"sample"`
+		Size   int64  `json:"size"`
+	}
+	var payload struct {
+		Files []preuploadFile `json:"files"`
+	}
+	for _, upload := range uploads {
+		payload.Files = append(payload.Files, preuploadFile{
+			Path:   upload.op.PathInRepo,
+			Sample: base64.StdEncoding.EncodeToString(upload.sample),
+			Size:   upload.size,
+		})
+	}
+	var response struct {
+		Files []struct {
+			Path         string `json:"path"`
+			UploadMode   string `json:"uploadMode"`
+			ShouldIgnore bool   `json:"shouldIgnore"`
+		} `json:"files"`
+	}
+	rawURL := fmt.Sprintf("%s/%s/%s/preupload/%s", r.httpClient.baseURL, repoType.apiPath(), id, url.PathEscape(revision))
+	if createPR {
+		rawURL += "?create_pr=1"
+	}
+	if err := r.httpClient.postURL(ctx, rawURL, payload, &response); err != nil {
+		return fmt.Errorf("failed to prepare upload: %w", err)
+	}
+
+	modes := make(map[string]string, len(response.Files))
+	for _, file := range response.Files {
+		modes[file.Path] = file.UploadMode
+		if file.ShouldIgnore {
+			modes[file.Path] = uploadModeIgnored
+		}
+	}
+	for _, upload := range uploads {
+		mode, ok := modes[upload.op.PathInRepo]
+		if !ok {
+			return fmt.Errorf("failed to prepare upload: no upload mode returned for %s", upload.op.PathInRepo)
+		}
+		upload.mode = mode
+	}
+	return nil
+}
+
+// uploadLFSFiles requests upload actions for files from the Git LFS batch API and performs them concurrently.
+// Files already in LFS storage come without actions and are not uploaded again.
+func (r *Repository) uploadLFSFiles(ctx context.Context, repoType RepoType, id string, revision string, uploads []*fileUpload, opts CommitOptions) error {
+	payload := lfsBatchRequest{
+		Operation: "upload",
+		Transfers: []string{"basic", "multipart"},
+		HashAlgo:  "sha256",
+		Ref:       &lfsRef{Name: revision},
+	}
+	for _, upload := range uploads {
+		payload.Objects = append(payload.Objects, lfsObjectSpec{OID: upload.sha256, Size: upload.size})
+	}
+	var response lfsBatchResponse
+	rawURL := fmt.Sprintf("%s/%s%s.git/info/lfs/objects/batch", r.httpClient.endpoint(), repoType.urlPrefix(), id)
+	if err := r.httpClient.postLFS(ctx, rawURL, nil, payload, &response); err != nil {
+		return fmt.Errorf("failed to request LFS upload: %w", err)
+	}
+
+	byOID := make(map[string]*fileUpload, len(uploads))
+	for _, upload := range uploads {
+		byOID[upload.sha256] = upload
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	maxWorkers := opts.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers
+	}
+	errs := make([]error, len(response.Objects))
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i, object := range response.Objects {
+		upload, ok := byOID[object.OID]
+		if !ok {
+			errs[i] = fmt.Errorf("unexpected LFS object %s", object.OID)
+			cancel()
+			break
+		}
+		if object.Error != nil {
+			errs[i] = fmt.Errorf("failed to upload %s: LFS error %d: %s", upload.op.PathInRepo, object.Error.Code, object.Error.Message)
+			cancel()
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := r.httpClient.uploadLFSObject(ctx, object, upload, opts.Progress); err != nil {
+				errs[i] = fmt.Errorf("failed to upload %s: %w", upload.op.PathInRepo, err)
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstError(errs)
+}
+
+// uploadLFSObject uploads a file to LFS storage with the actions returned by the batch API, in a single request
+// or in parts, and asks the Hub to verify it.
+func (c *HttpClient) uploadLFSObject(ctx context.Context, object lfsObject, upload *fileUpload, progressFunc UploadProgressFunc) error {
+	action := object.Actions.Upload
+	if action == nil {
+		return nil
+	}
+	content, size, err := upload.op.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	if size != upload.size {
+		return errors.New("file changed since it was hashed")
+	}
+
+	progress := &chunkProgress{total: size}
+	if progressFunc != nil {
+		progress.progress = func(uploaded int64, total int64) {
+			progressFunc(upload.op.PathInRepo, uploaded, total)
+		}
+	}
+	if chunkSize, ok := action.Header["chunk_size"]; ok {
+		err = c.uploadMultipart(ctx, action, upload.sha256, content, size, chunkSize, progress)
+	} else {
+		_, err = c.uploadPart(ctx, action.Href, action.Header, io.NewSectionReader(content, 0, size), progress)
+	}
+	if err != nil {
+		return err
+	}
+
+	if verify := object.Actions.Verify; verify != nil {
+		payload := lfsObjectSpec{OID: upload.sha256, Size: size}
+		if err := c.postLFS(ctx, verify.Href, verify.Header, payload, nil); err != nil {
+			return fmt.Errorf("failed to verify upload: %w", err)
+		}
+	}
+	return nil
+}
+
+// uploadMultipart uploads a file in parts of chunk size to the presigned URLs listed by the action header under
+// the part numbers, then completes the upload with the ETags of the parts.
+func (c *HttpClient) uploadMultipart(ctx context.Context, action *lfsAction, oid string, content io.ReaderAt, size int64, chunkSize string, progress *chunkProgress) error {
+	partSize, err := strconv.ParseInt(chunkSize, 10, 64)
+	if err != nil || partSize <= 0 {
+		return fmt.Errorf("invalid chunk size %q", chunkSize)
+	}
+	var numbers []int
+	for key := range action.Header {
+		if n, err := strconv.Atoi(key); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	slices.Sort(numbers)
+	if want := int((size + partSize - 1) / partSize); len(numbers) != want {
+		return fmt.Errorf("expected %d part URLs, got %d", want, len(numbers))
+	}
+
+	completion := lfsMultipartCompletion{OID: oid}
+	for i, n := range numbers {
+		offset := int64(i) * partSize
+		part := io.NewSectionReader(content, offset, min(partSize, size-offset))
+		etag, err := c.uploadPart(ctx, action.Header[strconv.Itoa(n)], nil, part, progress)
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", n, err)
+		}
+		completion.Parts = append(completion.Parts, lfsPart{PartNumber: n, ETag: etag})
+	}
+	if err := c.postLFS(ctx, action.Href, nil, completion, nil); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// uploadPart uploads content to a presigned URL with a PUT request and returns the ETag of the stored content.
+// Network and server errors are retried up to maxUploadRetries times, with an exponential backoff.
+func (c *HttpClient) uploadPart(ctx context.Context, rawURL string, header map[string]string, content *io.SectionReader, progress *chunkProgress) (string, error) {
+	for retries := 0; ; retries++ {
+		// The request is presigned, so it is sent without the API key.
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, nil)
+		if err != nil {
+			return "", err
+		}
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		body := &progressReader{r: io.NewSectionReader(content, 0, content.Size()), progress: progress}
+		req.Body = io.NopCloser(body)
+		req.ContentLength = content.Size()
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			if resp.StatusCode == http.StatusOK {
+				resp.Body.Close()
+				return resp.Header.Get("ETag"), nil
+			}
+			err = newAPIError(resp)
+			resp.Body.Close()
+		}
+		progress.add(-body.n)
+		var apiErr *APIError
+		if ctx.Err() != nil || retries >= maxUploadRetries ||
+			(errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError) {
+			return "", err
+		}
+		timer := time.NewTimer(uploadRetryBackoff << retries)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// postLFS sends a POST request to the Git LFS API with the given headers.
+func (c *HttpClient) postLFS(ctx context.Context, rawURL string, header map[string]string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize body: %v", err)
+	}
+	req, err := c.newURLRequest(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", lfsContentType)
+	req.Header.Set("Content-Type", lfsContentType)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	return c.doRequest(req, out)
+}
+
+// progressReader reports the number of bytes read through it.
+type progressReader struct {
+	r        io.Reader
+	n        int64
+	progress *chunkProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	r.progress.add(int64(n))
+	return n, err
+}
+
+type lfsBatchRequest struct {
+	Operation string          `json:"operation"`
+	Transfers []string        `json:"transfers"`
+	Objects   []lfsObjectSpec `json:"objects"`
+	HashAlgo  string          `json:"hash_algo"`
+	Ref       *lfsRef         `json:"ref,omitempty"`
+}
+
+type lfsRef struct {
+	Name string `json:"name"`
+}
+
+type lfsBatchResponse struct {
+	Transfer string      `json:"transfer"`
+	Objects  []lfsObject `json:"objects"`
+}
+
+type lfsObjectSpec struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsObject struct {
+	lfsObjectSpec
+	Actions struct {
+		Upload *lfsAction `json:"upload,omitempty"`
+		Verify *lfsAction `json:"verify,omitempty"`
+	} `json:"actions"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type lfsMultipartCompletion struct {
+	OID   string    `json:"oid"`
+	Parts []lfsPart `json:"parts"`
+}
+
+type lfsPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
@@ -0,0 +1,245 @@
+package huggo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRepository_CreateCommit_LFS(t *testing.T) {
+	model := []byte("0123456789")
+	big := []byte(strings.Repeat("abcde", 5))
+	exists := []byte("already uploaded")
+	oid := func(content []byte) string {
+		sum := sha256.Sum256(content)
+		return hex.EncodeToString(sum[:])
+	}
+
+	var mu sync.Mutex
+	uploaded := make(map[string][]byte)
+	verified := make(map[string]bool)
+	var parts []lfsPart
+	var lines []map[string]any
+	failures := 1
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/api/models/user/model/preupload/main", func(w http.ResponseWriter, r *http.Request) {
+		modes := map[string]string{"README.md": "regular", ".env": "regular"}
+		var payload struct {
+			Files []struct {
+				Path   string `json:"path"`
+				Sample string `json:"sample"`
+				Size   int64  `json:"size"`
+			} `json:"files"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		files := make([]map[string]any, len(payload.Files))
+		for i, file := range payload.Files {
+			mode, ok := modes[file.Path]
+			if !ok {
+				mode = "lfs"
+			}
+			files[i] = map[string]any{"path": file.Path, "uploadMode": mode, "shouldIgnore": file.Path == ".env"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+	})
+	mux.HandleFunc("/user/model.git/info/lfs/objects/batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != lfsContentType {
+			t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+		var payload lfsBatchRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Operation != "upload" || payload.Ref == nil || payload.Ref.Name != "main" {
+			t.Errorf("Unexpected batch request %+v", payload)
+		}
+		response := lfsBatchResponse{Transfer: "basic"}
+		for _, spec := range payload.Objects {
+			object := lfsObject{lfsObjectSpec: spec}
+			verify := &lfsAction{Href: server.URL + "/verify"}
+			switch object.OID {
+			case oid(model):
+				object.Actions.Upload = &lfsAction{Href: server.URL + "/storage/" + object.OID, Header: map[string]string{"X-Amz-Test": "1"}}
+				object.Actions.Verify = verify
+			case oid(big):
+				object.Actions.Upload = &lfsAction{Href: server.URL + "/complete", Header: map[string]string{
+					"chunk_size": "10",
+					"1":          server.URL + "/parts/1",
+					"2":          server.URL + "/parts/2",
+					"3":          server.URL + "/parts/3",
+				}}
+				object.Actions.Verify = verify
+			}
+			response.Objects = append(response.Objects, object)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	mux.HandleFunc("/storage/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Amz-Test") != "1" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		content, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		uploaded[strings.TrimPrefix(r.URL.Path, "/storage/")] = content
+	})
+	mux.HandleFunc("/parts/", func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		n := strings.TrimPrefix(r.URL.Path, "/parts/")
+		mu.Lock()
+		uploaded["part"+n] = content
+		mu.Unlock()
+		w.Header().Set("ETag", "etag"+n)
+	})
+	mux.HandleFunc("/complete", func(w http.ResponseWriter, r *http.Request) {
+		var payload lfsMultipartCompletion
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if payload.OID != oid(big) {
+			t.Errorf("Unexpected completion %+v", payload)
+		}
+		mu.Lock()
+		parts = payload.Parts
+		mu.Unlock()
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/verify", func(w http.ResponseWriter, r *http.Request) {
+		var payload lfsObjectSpec
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		verified[payload.OID] = true
+		mu.Unlock()
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/api/models/user/model/commit/main", func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			_ = json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"commitOid": "def456"})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	operations := []CommitOperation{
+		CommitOperationAdd{PathInRepo: "README.md", Content: []byte("# Model")},
+		CommitOperationAdd{PathInRepo: ".env", Content: []byte("SECRET=1")},
+		CommitOperationAdd{PathInRepo: "model.bin", Content: model},
+		CommitOperationAdd{PathInRepo: "big.bin", Content: big},
+		CommitOperationAdd{PathInRepo: "exists.bin", Content: exists},
+	}
+	var progressMu sync.Mutex
+	progress := make(map[string]int64)
+	_, err := repository.CreateCommit(context.Background(), RepoTypeModel, "user/model", "main", operations, "Upload weights", CommitOptions{
+		MaxWorkers: 2,
+		Progress: func(path string, uploaded int64, total int64) {
+			progressMu.Lock()
+			defer progressMu.Unlock()
+			progress[path] = uploaded
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(uploaded[oid(model)], model) {
+		t.Errorf("Unexpected uploaded content %q", uploaded[oid(model)])
+	}
+	for i, want := range []string{"abcdeabcde", "abcdeabcde", "abcde"} {
+		if got := string(uploaded[fmt.Sprint("part", i+1)]); got != want {
+			t.Errorf("Expected part %d %q, got %q", i+1, want, got)
+		}
+	}
+	if len(parts) != 3 || parts[0] != (lfsPart{PartNumber: 1, ETag: "etag1"}) || parts[2] != (lfsPart{PartNumber: 3, ETag: "etag3"}) {
+		t.Errorf("Unexpected completed parts %+v", parts)
+	}
+	if !verified[oid(model)] || !verified[oid(big)] || verified[oid(exists)] {
+		t.Errorf("Unexpected verified objects %v", verified)
+	}
+	if progress["model.bin"] != int64(len(model)) || progress["big.bin"] != int64(len(big)) || progress["exists.bin"] != 0 {
+		t.Errorf("Unexpected progress %v", progress)
+	}
+
+	want := []struct {
+		key  string
+		path string
+		oid  string
+	}{
+		{"header", "", ""},
+		{"file", "README.md", ""},
+		{"lfsFile", "model.bin", oid(model)},
+		{"lfsFile", "big.bin", oid(big)},
+		{"lfsFile", "exists.bin", oid(exists)},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %v", len(want), len(lines), lines)
+	}
+	for i, w := range want[1:] {
+		line := lines[i+1]
+		value, _ := line["value"].(map[string]any)
+		if line["key"] != w.key || value["path"] != w.path || (w.oid != "" && value["oid"] != w.oid) {
+			t.Errorf("Unexpected line %d: %v", i+1, line)
+		}
+	}
+}
+
+func TestRepository_CreateCommit_LFSObjectError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/preupload/main"):
+			_, _ = w.Write([]byte(`{"files":[{"path":"model.bin","uploadMode":"lfs"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/objects/batch"):
+			var payload lfsBatchRequest
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			fmt.Fprintf(w, `{"objects":[{"oid":%q,"size":3,"error":{"code":422,"message":"Invalid object"}}]}`, payload.Objects[0].OID)
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	operations := []CommitOperation{CommitOperationAdd{PathInRepo: "model.bin", Content: []byte("abc")}}
+	_, err := repository.CreateCommit(context.Background(), RepoTypeModel, "user/model", "main", operations, "Upload", CommitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "Invalid object") {
+		t.Errorf("Expected LFS object error, got %v", err)
+	}
+}
+
+func TestRepository_CreateCommit_UnknownUploadMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/preupload/main") {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			return
+		}
+		_, _ = w.Write([]byte(`{"files":[{"path":"model.bin","uploadMode":""}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	operations := []CommitOperation{CommitOperationAdd{PathInRepo: "model.bin", Content: []byte("abc")}}
+	_, err := repository.CreateCommit(context.Background(), RepoTypeModel, "user/model", "main", operations, "Upload", CommitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported upload mode") {
+		t.Errorf("Expected unsupported upload mode, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package huggo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ModelConfigFile is the name of the config file of transformers models.
+const ModelConfigFile = "config.json"
+
+// DefaultLoRARank is the rank of the adapters LoRA fine-tuning estimates assume.
+const DefaultLoRARank = 16
+
+// dtypeSizes maps the names of dtypes, as found in transformers configs and safetensors headers, to the number
+// of bytes an element takes.
+var dtypeSizes = map[string]float64{
+	"float64":  8,
+	"f64":      8,
+	"i64":      8,
+	"float32":  4,
+	"f32":      4,
+	"i32":      4,
+	"float16":  2,
+	"f16":      2,
+	"half":     2,
+	"bfloat16": 2,
+	"bf16":     2,
+	"i16":      2,
+	"float8":   1,
+	"f8_e4m3":  1,
+	"f8_e5m2":  1,
+	"int8":     1,
+	"i8":       1,
+	"u8":       1,
+	"bool":     1,
+	"int4":     0.5,
+}
+
+// DTypeSize returns the number of bytes an element of a dtype takes, e.g. 2 for "bfloat16" or "BF16".
+func DTypeSize(dtype string) (float64, bool) {
+	size, ok := dtypeSizes[strings.ToLower(dtype)]
+	return size, ok
+}
+
+// GetModelConfig fetches the config.json file of a transformers model at a revision, which describes its
+// architecture in more detail than the config returned along with the model info.
+func (r *Repository) GetModelConfig(ctx context.Context, id string, revision string) (*ModelConfig, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	var config ModelConfig
+	rawURL := r.httpClient.resolveURL(RepoTypeModel, id, revision, ModelConfigFile)
+	if err := r.httpClient.getURL(ctx, rawURL, &config); err != nil {
+		return nil, fmt.Errorf("failed to get model config: %w", err)
+	}
+	return &config, nil
+}
+
+// GetMemoryEstimate estimates the memory footprint of a transformers model stored in safetensors files, without
+// downloading its weights. The weights are counted in dtype, or in the dtype of the config if empty.
+// See EstimateMemory.
+func (r *Repository) GetMemoryEstimate(ctx context.Context, id string, revision string, dtype string, seqLen int, batch int) (*MemoryEstimate, error) {
+	metadata, err := r.GetSafetensorsMetadata(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	// The config is read at the same commit as the weights.
+	config, err := r.GetModelConfig(ctx, id, metadata.CommitHash)
+	if err != nil {
+		return nil, err
+	}
+	if dtype == "" {
+		dtype = cmp.Or(config.languageModel().TorchDType, config.TorchDType)
+	}
+	return EstimateMemory(config, metadata.TotalParameters(), dtype, seqLen, batch)
+}
+
+// EstimateMemory estimates the memory footprint in bytes of a transformers model with the given number of
+// parameters loaded in dtype, for sequences of seqLen tokens processed in batches of batch sequences.
+//
+// Inference memory covers the weights and the KV cache, which is stored in dtype or in 16 bits for integer
+// dtypes. Full fine-tuning assumes mixed precision training with Adam: weights and gradients in dtype, fp32
+// master weights and two fp32 moments per parameter. LoRA fine-tuning assumes frozen weights and fp32 adapters
+// of rank DefaultLoRARank on the four attention projections. Activations are not accounted for.
+func EstimateMemory(config *ModelConfig, parameters int64, dtype string, seqLen int, batch int) (*MemoryEstimate, error) {
+	size, ok := DTypeSize(dtype)
+	if !ok {
+		return nil, fmt.Errorf("unsupported dtype %q", dtype)
+	}
+	lm := config.languageModel()
+	if lm.NumHiddenLayers == 0 || lm.NumAttentionHeads == 0 || lm.HiddenSize == 0 {
+		return nil, errors.New("config does not describe the layers of the model")
+	}
+	kvHeads := lm.NumKeyValueHeads
+	if kvHeads == 0 {
+		kvHeads = lm.NumAttentionHeads
+	}
+	headDim := lm.HeadDim
+	if headDim == 0 {
+		headDim = lm.HiddenSize / lm.NumAttentionHeads
+	}
+
+	estimate := &MemoryEstimate{
+		Parameters: parameters,
+		Weights:    int64(math.Ceil(float64(parameters) * size)),
+		// Keys and values are cached for every layer, token and key/value head.
+		KVCache: 2 * int64(lm.NumHiddenLayers) * int64(kvHeads) * int64(headDim) * int64(seqLen) * int64(batch) * int64(max(size, 2)),
+	}
+	estimate.Inference = estimate.Weights + estimate.KVCache
+
+	trainingSize := max(size, 2)
+	bytesPerParameter := 2*trainingSize + 8
+	if trainingSize < 4 {
+		bytesPerParameter += 4
+	}
+	estimate.FullFineTuning = int64(math.Ceil(float64(parameters) * bytesPerParameter))
+
+	loraParameters := int64(lm.NumHiddenLayers) * 4 * DefaultLoRARank * 2 * int64(lm.HiddenSize)
+	estimate.LoRAFineTuning = estimate.Weights + loraParameters*16
+	return estimate, nil
+}
+
+// EstimateGGUFMemory estimates the memory footprint in bytes of the model of a GGUF file, for sequences of
+// seqLen tokens processed in batches of batch sequences. The weights are counted as stored in the file, and the
+// KV cache is assumed to be stored in 16 bits. Fine-tuning estimates are left empty.
+func EstimateGGUFMemory(gguf *GGUFFile, seqLen int, batch int) (*MemoryEstimate, error) {
+	layers := gguf.ArchitectureUint("block_count")
+	heads := gguf.ArchitectureUint("attention.head_count")
+	if layers == 0 || heads == 0 {
+		return nil, errors.New("GGUF metadata does not describe the layers of the model")
+	}
+	kvHeads := gguf.ArchitectureUint("attention.head_count_kv")
+	if kvHeads == 0 {
+		kvHeads = heads
+	}
+	keyLength, valueLength := gguf.ArchitectureUint("attention.key_length"), gguf.ArchitectureUint("attention.value_length")
+	if keyLength == 0 {
+		keyLength = gguf.ArchitectureUint("embedding_length") / heads
+	}
+	if valueLength == 0 {
+		valueLength = keyLength
+	}
+
+	estimate := &MemoryEstimate{
+		Parameters: gguf.ParameterCount(),
+		Weights:    gguf.TensorDataSize(),
+		KVCache:    int64(layers*kvHeads*(keyLength+valueLength)) * int64(seqLen*batch) * 2,
+	}
+	estimate.Inference = estimate.Weights + estimate.KVCache
+	return estimate, nil
+}
+
+// languageModel returns the config describing the language model, which is nested in multimodal configs.
+func (c *ModelConfig) languageModel() *ModelConfig {
+	if c.NumHiddenLayers == 0 && c.TextConfig != nil {
+		return c.TextConfig
+	}
+	return c
+}
+
+// MemoryEstimate is the estimated memory footprint of a model in bytes.
+type MemoryEstimate struct {
+	Parameters int64
+	Weights    int64
+	KVCache    int64
+	// Inference is the memory needed to serve the model: its weights and KV cache.
+	Inference      int64
+	LoRAFineTuning int64
+	FullFineTuning int64
+}
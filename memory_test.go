@@ -0,0 +1,99 @@
+package huggo
+
+import (
+	"testing"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	// The dimensions of Llama 3 8B.
+	config := &ModelConfig{
+		HiddenSize:        4096,
+		NumHiddenLayers:   32,
+		NumAttentionHeads: 32,
+		NumKeyValueHeads:  8,
+	}
+	tests := []struct {
+		name  string
+		dtype string
+		want  MemoryEstimate
+	}{
+		{
+			name:  "bfloat16",
+			dtype: "bfloat16",
+			want: MemoryEstimate{
+				Parameters:     8_000_000_000,
+				Weights:        16_000_000_000,
+				KVCache:        2 * 32 * 8 * 128 * 8192 * 2 * 2,
+				Inference:      16_000_000_000 + 2*32*8*128*8192*2*2,
+				LoRAFineTuning: 16_000_000_000 + 32*4*16*2*4096*16,
+				FullFineTuning: 8_000_000_000 * 16,
+			},
+		},
+		{
+			name:  "int4",
+			dtype: "int4",
+			want: MemoryEstimate{
+				Parameters:     8_000_000_000,
+				Weights:        4_000_000_000,
+				KVCache:        2 * 32 * 8 * 128 * 8192 * 2 * 2,
+				Inference:      4_000_000_000 + 2*32*8*128*8192*2*2,
+				LoRAFineTuning: 4_000_000_000 + 32*4*16*2*4096*16,
+				FullFineTuning: 8_000_000_000 * 16,
+			},
+		},
+		{
+			name:  "safetensors dtype",
+			dtype: "F32",
+			want: MemoryEstimate{
+				Parameters:     8_000_000_000,
+				Weights:        32_000_000_000,
+				KVCache:        2 * 32 * 8 * 128 * 8192 * 2 * 4,
+				Inference:      32_000_000_000 + 2*32*8*128*8192*2*4,
+				LoRAFineTuning: 32_000_000_000 + 32*4*16*2*4096*16,
+				FullFineTuning: 8_000_000_000 * 16,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateMemory(config, 8_000_000_000, tt.dtype, 8192, 2)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+
+	if _, err := EstimateMemory(config, 1, "float12", 1, 1); err == nil {
+		t.Errorf("Expected error for unsupported dtype")
+	}
+	multimodal := &ModelConfig{TextConfig: config}
+	if _, err := EstimateMemory(multimodal, 1, "float16", 1, 1); err != nil {
+		t.Errorf("Unexpected error for multimodal config: %v", err)
+	}
+}
+
+func TestEstimateGGUFMemory(t *testing.T) {
+	gguf := &GGUFFile{
+		Metadata: map[string]any{
+			"general.architecture":          "llama",
+			"llama.block_count":             uint32(32),
+			"llama.embedding_length":        uint32(4096),
+			"llama.attention.head_count":    uint32(32),
+			"llama.attention.head_count_kv": uint32(8),
+		},
+		Tensors: []GGUFTensorInfo{{Name: "w", Shape: []uint64{4096, 4096}, Type: GGMLTypeQ4_K}},
+	}
+	got, err := EstimateGGUFMemory(gguf, 4096, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := int64(4096 * 4096 / 256 * 144); got.Weights != want {
+		t.Errorf("Expected weights of %d bytes, got %d", want, got.Weights)
+	}
+	if want := int64(32 * 8 * 256 * 4096 * 2); got.KVCache != want || got.Inference != got.Weights+want {
+		t.Errorf("Unexpected KV cache %d", got.KVCache)
+	}
+}
@@ -0,0 +1,116 @@
+package huggo
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Papers struct {
+	httpClient *HttpClient
+}
+
+// NewPapers creates a client to interact with the Papers API.
+func NewPapers(httpClient *HttpClient) *Papers {
+	return &Papers{httpClient: httpClient}
+}
+
+// GetDailyPapers fetches the papers featured on the daily papers page for the given date.
+// A zero date fetches the latest daily papers.
+func (p *Papers) GetDailyPapers(date time.Time) ([]DailyPaper, error) {
+	path := "/daily_papers"
+	if !date.IsZero() {
+		path += "?date=" + date.Format(time.DateOnly)
+	}
+	var papers []DailyPaper
+	err := p.httpClient.Get(path, &papers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily papers: %w", err)
+	}
+	return papers, nil
+}
+
+// SearchPapers searches papers by title, authors and content.
+func (p *Papers) SearchPapers(query string) ([]Paper, error) {
+	var results []DailyPaper
+	path := "/papers/search?q=" + url.QueryEscape(query)
+	err := p.httpClient.Get(path, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search papers: %w", err)
+	}
+	papers := make([]Paper, len(results))
+	for i, result := range results {
+		papers[i] = result.Paper
+	}
+	return papers, nil
+}
+
+// GetPaper fetches a paper by its arXiv ID.
+func (p *Papers) GetPaper(arxivID string) (*Paper, error) {
+	var paper Paper
+	path := "/papers/" + url.PathEscape(arxivID)
+	err := p.httpClient.Get(path, &paper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper: %w", err)
+	}
+	return &paper, nil
+}
+
+// GetPaperRepos fetches the models, datasets and spaces citing a paper through their "arxiv:" tag.
+func (p *Papers) GetPaperRepos(arxivID string) (*PaperRepos, error) {
+	var repos PaperRepos
+	filter := "?filter=" + url.QueryEscape("arxiv:"+arxivID)
+	err := p.httpClient.Get("/models"+filter, &repos.Models)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper models: %w", err)
+	}
+	err = p.httpClient.Get("/datasets"+filter, &repos.Datasets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper datasets: %w", err)
+	}
+	err = p.httpClient.Get("/spaces"+filter, &repos.Spaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper spaces: %w", err)
+	}
+	return &repos, nil
+}
+
+type Paper struct {
+	ID                 string        `json:"id"`
+	Title              string        `json:"title"`
+	Summary            string        `json:"summary"`
+	Authors            []PaperAuthor `json:"authors"`
+	PublishedAt        time.Time     `json:"publishedAt"`
+	SubmittedOnDailyAt *time.Time    `json:"submittedOnDailyAt,omitempty"`
+	Upvotes            int64         `json:"upvotes"`
+	DiscussionID       string        `json:"discussionId,omitempty"`
+	AISummary          string        `json:"ai_summary,omitempty"`
+	AIKeywords         []string      `json:"ai_keywords,omitempty"`
+	GithubRepo         string        `json:"githubRepo,omitempty"`
+	GithubStars        int64         `json:"githubStars,omitempty"`
+	ProjectPage        string        `json:"projectPage,omitempty"`
+}
+
+type PaperAuthor struct {
+	ID     string           `json:"_id"`
+	Name   string           `json:"name"`
+	Hidden bool             `json:"hidden"`
+	Status string           `json:"status,omitempty"`
+	User   *CollectionOwner `json:"user,omitempty"`
+}
+
+type DailyPaper struct {
+	Paper       Paper            `json:"paper"`
+	Title       string           `json:"title"`
+	Summary     string           `json:"summary"`
+	PublishedAt time.Time        `json:"publishedAt"`
+	Thumbnail   string           `json:"thumbnail,omitempty"`
+	NumComments int64            `json:"numComments"`
+	SubmittedBy *CollectionOwner `json:"submittedBy,omitempty"`
+}
+
+type PaperRepos struct {
+	Models   []Model   `json:"models"`
+	Datasets []Dataset `json:"datasets"`
+	Spaces   []Space   `json:"spaces"`
+}
@@ -0,0 +1,146 @@
+package huggo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newPapersServer(t *testing.T, wantPath string, wantQuery string, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != wantPath || r.URL.RawQuery != wantQuery {
+			t.Errorf("Unexpected request %s?%s", r.URL.EscapedPath(), r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestPapers_GetDailyPapers(t *testing.T) {
+	body := `[{
+		"paper": {"id": "2401.00001", "title": "A Paper", "upvotes": 42, "authors": [{"_id": "a1", "name": "Ada"}]},
+		"title": "A Paper",
+		"publishedAt": "2024-01-01T00:00:00.000Z",
+		"numComments": 3,
+		"submittedBy": {"name": "user"}
+	}]`
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		wantQuery string
+	}{
+		{name: "latest", wantQuery: ""},
+		{name: "date", date: time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC), wantQuery: "date=2024-01-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPapersServer(t, "/api/daily_papers", tt.wantQuery, body)
+			defer server.Close()
+
+			client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+			papers, err := NewPapers(client).GetDailyPapers(tt.date)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(papers) != 1 {
+				t.Fatalf("Expected 1 paper, got %d", len(papers))
+			}
+			paper := papers[0]
+			if paper.Paper.ID != "2401.00001" || paper.Paper.Upvotes != 42 || paper.NumComments != 3 {
+				t.Errorf("Unexpected daily paper %+v", paper)
+			}
+			if len(paper.Paper.Authors) != 1 || paper.Paper.Authors[0].Name != "Ada" {
+				t.Errorf("Unexpected authors %+v", paper.Paper.Authors)
+			}
+			if !paper.PublishedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("Unexpected published date %v", paper.PublishedAt)
+			}
+		})
+	}
+}
+
+func TestPapers_SearchPapers(t *testing.T) {
+	server := newPapersServer(t, "/api/papers/search", "q=attention+is+all", `[
+		{"paper": {"id": "1706.03762", "title": "Attention Is All You Need"}},
+		{"paper": {"id": "2401.00001", "title": "Attention Again"}}
+	]`)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	papers, err := NewPapers(client).SearchPapers("attention is all")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(papers) != 2 || papers[0].ID != "1706.03762" || papers[1].Title != "Attention Again" {
+		t.Errorf("Unexpected papers %+v", papers)
+	}
+}
+
+func TestPapers_GetPaper(t *testing.T) {
+	tests := []struct {
+		name     string
+		arxivID  string
+		wantPath string
+	}{
+		{name: "new identifier", arxivID: "1706.03762", wantPath: "/api/papers/1706.03762"},
+		{name: "escaped identifier", arxivID: "hep-th/9901001", wantPath: "/api/papers/hep-th%2F9901001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPapersServer(t, tt.wantPath, "", `{
+				"id": "1706.03762",
+				"title": "Attention Is All You Need",
+				"publishedAt": "2017-06-12T00:00:00.000Z",
+				"ai_keywords": ["transformer"],
+				"githubRepo": "https://github.com/tensorflow/tensor2tensor"
+			}`)
+			defer server.Close()
+
+			client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+			paper, err := NewPapers(client).GetPaper(tt.arxivID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if paper.Title != "Attention Is All You Need" || len(paper.AIKeywords) != 1 || paper.GithubRepo == "" {
+				t.Errorf("Unexpected paper %+v", paper)
+			}
+		})
+	}
+}
+
+func TestPapers_GetPaperRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("filter"); got != "arxiv:1706.03762" {
+			t.Errorf("Unexpected filter %q", got)
+		}
+		switch r.URL.Path {
+		case "/api/models":
+			_, _ = w.Write([]byte(`[{"id": "user/model"}, {"id": "user/other"}]`))
+		case "/api/datasets":
+			_, _ = w.Write([]byte(`[{"id": "user/dataset"}]`))
+		case "/api/spaces":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repos, err := NewPapers(client).GetPaperRepos("1706.03762")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(repos.Models) != 2 || repos.Models[0].ID != "user/model" {
+		t.Errorf("Unexpected models %+v", repos.Models)
+	}
+	if len(repos.Datasets) != 1 || repos.Datasets[0].Welcome8ID != "user/dataset" {
+		t.Errorf("Unexpected datasets %+v", repos.Datasets)
+	}
+	if len(repos.Spaces) != 0 {
+		t.Errorf("Unexpected spaces %+v", repos.Spaces)
+	}
+}
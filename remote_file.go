@@ -0,0 +1,253 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+const (
+	// remoteFileBlockSize is the granularity at which remote files are fetched and cached.
+	remoteFileBlockSize = 64 << 10
+	// remoteFileCacheBlocks is the number of blocks a remote file keeps in memory.
+	remoteFileCacheBlocks = 64
+)
+
+// OpenRemoteFile opens a repository file at a revision for random access without downloading it.
+// Reads are served with Range requests and the most recently read blocks are kept in memory, so that parsers
+// reading headers or indexes fetch only the bytes they need.
+//
+// The revision is resolved to a commit when the file is opened, and the CDN URL LFS files redirect to is reused
+// by later reads instead of going through the Hub every time.
+func (r *Repository) OpenRemoteFile(ctx context.Context, repoType RepoType, id string, revision string, filename string) (*RemoteFile, error) {
+	metadata, err := r.GetFileMetadata(ctx, repoType, id, revision, filename)
+	if err != nil {
+		return nil, err
+	}
+	f := &RemoteFile{
+		ctx:        ctx,
+		httpClient: r.httpClient,
+		resolveURL: r.httpClient.resolveURL(repoType, id, metadata.CommitHash, filename),
+		metadata:   metadata,
+		blocks:     make(map[int64][]byte),
+	}
+	f.location = f.resolveURL
+	if metadata.LFS && metadata.Location != "" {
+		f.location = metadata.Location
+	}
+	return f, nil
+}
+
+// RemoteFile is a repository file read lazily over HTTP. It implements io.ReaderAt, io.ReadSeeker and io.Closer.
+// ReadAt is safe for concurrent use, Read and Seek are not.
+type RemoteFile struct {
+	ctx        context.Context
+	httpClient *HttpClient
+	resolveURL string
+	metadata   *FileMetadata
+	offset     int64
+
+	mu       sync.Mutex
+	location string
+	blocks   map[int64][]byte
+	// recent lists the cached blocks from the least to the most recently used.
+	recent []int64
+}
+
+// Size returns the size of the file in bytes.
+func (f *RemoteFile) Size() int64 {
+	return f.metadata.Size
+}
+
+// CommitHash returns the commit the revision of the file resolved to.
+func (f *RemoteFile) CommitHash() string {
+	return f.metadata.CommitHash
+}
+
+// ReadAt reads len(p) bytes of the file starting at off.
+func (f *RemoteFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= f.Size() {
+		return 0, io.EOF
+	}
+	end := min(off+int64(len(p)), f.Size())
+	first, last := off/remoteFileBlockSize, (end-1)/remoteFileBlockSize
+	blocks, err := f.readBlocks(first, last)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i, block := range blocks {
+		start := (first + int64(i)) * remoteFileBlockSize
+		n += copy(p[n:], block[max(off-start, 0):])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read reads up to len(p) bytes from the current offset.
+func (f *RemoteFile) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the offset of the next Read.
+func (f *RemoteFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.Size()
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close releases the cached blocks.
+func (f *RemoteFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blocks = make(map[int64][]byte)
+	f.recent = nil
+	return nil
+}
+
+// readBlocks returns the blocks from first to last, fetching the runs of blocks that are not cached with one
+// request each.
+func (f *RemoteFile) readBlocks(first int64, last int64) ([][]byte, error) {
+	blocks := make([][]byte, last-first+1)
+	f.mu.Lock()
+	for i := range blocks {
+		blocks[i] = f.cachedBlock(first + int64(i))
+	}
+	f.mu.Unlock()
+
+	for i := 0; i < len(blocks); {
+		if blocks[i] != nil {
+			i++
+			continue
+		}
+		j := i
+		for j < len(blocks) && blocks[j] == nil {
+			j++
+		}
+		start := (first + int64(i)) * remoteFileBlockSize
+		end := min((first+int64(j))*remoteFileBlockSize, f.Size())
+		data, err := f.fetch(start, end)
+		if err != nil {
+			return nil, err
+		}
+		f.mu.Lock()
+		for k := i; k < j; k++ {
+			block := data[int64(k-i)*remoteFileBlockSize : min(int64(k-i+1)*remoteFileBlockSize, int64(len(data)))]
+			blocks[k] = block
+			// Blocks are copied so that the cache does not keep the whole fetched range alive.
+			f.cacheBlock(first+int64(k), bytes.Clone(block))
+		}
+		f.mu.Unlock()
+		i = j
+	}
+	return blocks, nil
+}
+
+// cachedBlock returns a cached block and marks it as the most recently used, or nil if it is not cached.
+// It must be called with f.mu held.
+func (f *RemoteFile) cachedBlock(index int64) []byte {
+	block, ok := f.blocks[index]
+	if ok {
+		f.recent = slices.DeleteFunc(f.recent, func(i int64) bool { return i == index })
+		f.recent = append(f.recent, index)
+	}
+	return block
+}
+
+// cacheBlock caches a block, evicting the least recently used one if the cache is full.
+// It must be called with f.mu held.
+func (f *RemoteFile) cacheBlock(index int64, block []byte) {
+	if _, ok := f.blocks[index]; !ok && len(f.recent) >= remoteFileCacheBlocks {
+		delete(f.blocks, f.recent[0])
+		f.recent = f.recent[1:]
+	}
+	if _, ok := f.blocks[index]; ok {
+		f.recent = slices.DeleteFunc(f.recent, func(i int64) bool { return i == index })
+	}
+	f.blocks[index] = block
+	f.recent = append(f.recent, index)
+}
+
+// fetch reads the bytes from start to end (exclusive) of the file. The CDN URL of the file is used first, and
+// is renewed through the Hub if it is rejected, as such URLs are signed and expire.
+func (f *RemoteFile) fetch(start int64, end int64) ([]byte, error) {
+	f.mu.Lock()
+	location := f.location
+	f.mu.Unlock()
+
+	data, err := f.fetchURL(location, start, end)
+	var apiErr *APIError
+	if err != nil && location != f.resolveURL && errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+		metadata, headErr := f.httpClient.headFile(f.ctx, f.resolveURL)
+		if headErr != nil {
+			return nil, fmt.Errorf("failed to renew file location: %w", headErr)
+		}
+		f.mu.Lock()
+		f.location = metadata.Location
+		f.mu.Unlock()
+		data, err = f.fetchURL(metadata.Location, start, end)
+	}
+	return data, err
+}
+
+func (f *RemoteFile) fetchURL(rawURL string, start int64, end int64) ([]byte, error) {
+	req, err := f.httpClient.newURLRequest(f.ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The API key is only sent to the Hub, not to the CDN files are served from.
+	if hub, err := url.Parse(f.resolveURL); err == nil && req.URL.Host != hub.Host {
+		req.Header.Del("Authorization")
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	resp, err := f.httpClient.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range, so the bytes before it are skipped.
+		if _, err := io.CopyN(io.Discard, resp.Body, start); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, newAPIError(resp)
+	}
+	data := make([]byte, end-start)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,109 @@
+package huggo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRepository_OpenRemoteFile(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	var b strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&b, "%08d", i)
+	}
+	content := b.String()
+	sum := sha256.Sum256([]byte(content))
+
+	// The CDN only accepts the latest signed URL.
+	var token, cdnRequests atomic.Int32
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cdnRequests.Add(1)
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Unexpected Authorization header sent to the CDN")
+		}
+		if r.URL.Query().Get("token") != fmt.Sprint(token.Load()) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.ServeContent(w, r, "model.gguf", time.Time{}, strings.NewReader(content))
+	}))
+	defer cdn.Close()
+
+	var heads atomic.Int32
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Unexpected %s request to the Hub", r.Method)
+		}
+		heads.Add(1)
+		w.Header().Set("X-Repo-Commit", commit)
+		w.Header().Set("X-Linked-Etag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("X-Linked-Size", fmt.Sprint(len(content)))
+		w.Header().Set("Location", fmt.Sprintf("%s/model.gguf?token=%d", cdn.URL, token.Load()))
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer hub.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(hub.URL+"/api"))
+	repository := NewRepository(client)
+
+	f, err := repository.OpenRemoteFile(context.Background(), RepoTypeModel, "user/model", "main", "model.gguf")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+	if f.Size() != int64(len(content)) || f.CommitHash() != commit {
+		t.Errorf("Unexpected size %d or commit %s", f.Size(), f.CommitHash())
+	}
+
+	buf := make([]byte, 16)
+	if _, err := f.ReadAt(buf, 100000); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(buf) != content[100000:100016] {
+		t.Errorf("Unexpected content %q", buf)
+	}
+	// Reads within cached blocks do not send requests.
+	if _, err := f.ReadAt(buf, 100100); err != nil || string(buf) != content[100100:100116] {
+		t.Errorf("Unexpected content %q: %v", buf, err)
+	}
+	if n := cdnRequests.Load(); n != 1 {
+		t.Errorf("Expected 1 CDN request, got %d", n)
+	}
+
+	// Expired CDN URLs are renewed through the Hub.
+	token.Add(1)
+	if _, err := f.Seek(-10, io.SeekEnd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tail, err := io.ReadAll(f)
+	if err != nil || string(tail) != content[len(content)-10:] {
+		t.Errorf("Unexpected tail %q: %v", tail, err)
+	}
+	if n := heads.Load(); n != 2 {
+		t.Errorf("Expected 2 HEAD requests, got %d", n)
+	}
+
+	// Reads spanning several blocks return every byte.
+	whole := make([]byte, len(content))
+	n, err := f.ReadAt(whole, 0)
+	if err != nil || n != len(content) || string(whole) != content {
+		t.Errorf("Unexpected read of %d bytes: %v", n, err)
+	}
+	for index, block := range f.blocks {
+		if cap(block) > remoteFileBlockSize {
+			t.Errorf("Expected block %d to hold at most %d bytes, got %d", index, remoteFileBlockSize, cap(block))
+		}
+	}
+	if _, err := f.ReadAt(buf, int64(len(content))-8); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
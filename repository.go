@@ -1,6 +1,47 @@
 package huggo
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultRevision is the revision used when none is specified.
+const DefaultRevision = "main"
+
+// RepoType is the type of a repository.
+type RepoType string
+
+const (
+	RepoTypeModel   RepoType = "model"
+	RepoTypeDataset RepoType = "dataset"
+	RepoTypeSpace   RepoType = "space"
+)
+
+// apiPath returns the API path segment under which repositories of this type live, e.g. "datasets".
+func (t RepoType) apiPath() string {
+	switch t {
+	case RepoTypeDataset:
+		return "datasets"
+	case RepoTypeSpace:
+		return "spaces"
+	default:
+		return "models"
+	}
+}
+
+// urlPrefix returns the prefix of the URLs of repositories of this type, e.g. "datasets/".
+// Models have no prefix.
+func (t RepoType) urlPrefix() string {
+	switch t {
+	case RepoTypeDataset:
+		return "datasets/"
+	case RepoTypeSpace:
+		return "spaces/"
+	default:
+		return ""
+	}
+}
 
 type Repository struct {
 	httpClient *HttpClient
@@ -12,34 +53,74 @@ func NewRepository(httpClient *HttpClient) *Repository {
 }
 
 type CreateRepositoryPayload struct {
-	// Type specifies the type of repository. Valid values are "dataset", "space", or "model". Defaults to "model".
-	Type string `json:"type"`
+	// Type specifies the type of repository. Defaults to RepoTypeModel.
+	Type RepoType `json:"type,omitempty"`
 	// Name is the name of the repository.
 	Name string `json:"name"`
 	// Organization is the organization ID in which to create the repository.
-	Organization string `json:"organization"`
-	// Visibility determines if the repository is "public" or "private".
-	Visibility string `json:"private"`
+	Organization string `json:"organization,omitempty"`
+	// Private determines if the repository is private.
+	Private bool `json:"private"`
 	// SDK specifies the SDK to use for the repository when its type is "space". Valid values are "streamlit", "gradio", "docker", or "static".
 	SDK string `json:"sdk,omitempty"`
+	// Hardware specifies the hardware of a space, e.g. "cpu-basic" or "t4-small".
+	Hardware string `json:"hardware,omitempty"`
+	// Storage specifies the persistent storage tier of a space. Valid values are "small", "medium", or "large".
+	Storage string `json:"storageTier,omitempty"`
+	// SleepTime is the number of seconds of inactivity after which a space goes to sleep.
+	SleepTime int64 `json:"sleepTimeSeconds,omitempty"`
+	// Secrets are the secrets available to a space.
+	Secrets []SpaceSecret `json:"secrets,omitempty"`
+	// Variables are the environment variables available to a space.
+	Variables []SpaceVariable `json:"variables,omitempty"`
+	// ExistOK makes creating a repository that already exists succeed instead of failing.
+	ExistOK bool `json:"-"`
+}
+
+type SpaceSecret struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
 }
 
-// CreateRepository creates a new repository.
-func (r *Repository) CreateRepository(payload CreateRepositoryPayload) error {
-	err := r.httpClient.Post("/repos/create", payload, nil)
+type SpaceVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateRepository creates a new repository and returns its URL.
+func (r *Repository) CreateRepository(payload CreateRepositoryPayload) (string, error) {
+	var created struct {
+		URL string `json:"url"`
+	}
+	err := r.httpClient.Post("/repos/create", payload, &created)
+	var apiErr *APIError
+	if payload.ExistOK && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return r.repositoryURL(payload.Type, payload.Organization, payload.Name), nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create repository: %v", err)
+		return "", fmt.Errorf("failed to create repository: %w", err)
 	}
-	return nil
+	return created.URL, nil
+}
+
+// repositoryURL returns the URL of a repository.
+func (r *Repository) repositoryURL(repoType RepoType, namespace string, name string) string {
+	id := name
+	if namespace != "" {
+		id = namespace + "/" + name
+	}
+	return r.httpClient.endpoint() + "/" + repoType.urlPrefix() + id
 }
 
 type DeleteRepositoryPayload struct {
-	// Type specifies the type of repository. Valid values are "dataset", "space", or "model". Defaults to "model".
-	Type string `json:"type"`
+	// Type specifies the type of repository. Defaults to RepoTypeModel.
+	Type RepoType `json:"type,omitempty"`
 	// Name is the name of the repository.
 	Name string `json:"name"`
 	// Organization is the organization id in which to create the repository.
-	Organization string `json:"organizatoin,omitempty"`
+	Organization string `json:"organization,omitempty"`
 }
 
 // DeleteRepository deletes a repository.
@@ -52,8 +133,8 @@ func (r *Repository) DeleteRepository(payload DeleteRepositoryPayload) error {
 }
 
 type MoveRepositoryPayload struct {
-	// Type specifies the type of repository. Valid values are "dataset", "space", or "model". Defaults to "model".
-	Type string `json:"type"`
+	// Type specifies the type of repository. Defaults to RepoTypeModel.
+	Type RepoType `json:"type,omitempty"`
 	// From is the current name of the repository.
 	From string `json:"fromRepo"`
 	// To is the new name for the repository.
@@ -70,16 +151,26 @@ func (r *Repository) MoveRepository(payload MoveRepositoryPayload) error {
 }
 
 type UpdateVisibilityPayload struct {
-	// Visibility determines if the repository should be "public" or "private".
-	Visibility string `json:"private"`
+	// Private determines if the repository should be private.
+	Private bool `json:"private"`
 }
 
 // UpdateRepositoryVisibility updates the repository's visibility.
-func (r *Repository) UpdateRepositoryVisibility(repositoryType string, repositoryID string, payload UpdateVisibilityPayload) error {
-	path := fmt.Sprintf("/repos/%s/%s", repositoryType, repositoryID)
+func (r *Repository) UpdateRepositoryVisibility(repoType RepoType, repositoryID string, payload UpdateVisibilityPayload) error {
+	path := fmt.Sprintf("/%s/%s/settings", repoType.apiPath(), repositoryID)
 	err := r.httpClient.Put(path, payload, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create repository: %v", err)
+		return fmt.Errorf("failed to update repository visibility: %v", err)
 	}
 	return nil
 }
+
+// LFSInfo describes a file stored with Git LFS.
+type LFSInfo struct {
+	// SHA256 is the sha256 hash of the file content.
+	SHA256 string `json:"oid"`
+	// Size is the size of the file content in bytes.
+	Size int64 `json:"size"`
+	// PointerSize is the size of the LFS pointer file stored in git in bytes.
+	PointerSize int64 `json:"pointerSize"`
+}
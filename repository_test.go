@@ -0,0 +1,66 @@
+package huggo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRepository_CreateRepository(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/repos/create" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received["name"] == "existing" {
+			http.Error(w, `{"error":"You already created this space repo"}`, http.StatusConflict)
+			return
+		}
+		_, _ = w.Write([]byte(`{"url":"https://huggingface.co/spaces/org/demo","name":"org/demo"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	url, err := repository.CreateRepository(CreateRepositoryPayload{
+		Type:         RepoTypeSpace,
+		Name:         "demo",
+		Organization: "org",
+		Private:      true,
+		SDK:          "gradio",
+		Hardware:     "t4-small",
+		Secrets:      []SpaceSecret{{Key: "TOKEN", Value: "secret"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if url != "https://huggingface.co/spaces/org/demo" {
+		t.Errorf("Unexpected URL: %s", url)
+	}
+	if received["private"] != true || received["type"] != "space" || received["organization"] != "org" || received["hardware"] != "t4-small" {
+		t.Errorf("Unexpected payload: %v", received)
+	}
+
+	_, err = repository.CreateRepository(CreateRepositoryPayload{Type: RepoTypeSpace, Name: "existing", Organization: "org"})
+	if err == nil {
+		t.Errorf("Expected error for existing repository")
+	}
+	url, err = repository.CreateRepository(CreateRepositoryPayload{Type: RepoTypeSpace, Name: "existing", Organization: "org", ExistOK: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if url != server.URL+"/spaces/org/existing" {
+		t.Errorf("Unexpected URL: %s", url)
+	}
+}
+
+func TestDeleteRepositoryPayload_MarshalJSON(t *testing.T) {
+	data, _ := json.Marshal(DeleteRepositoryPayload{Type: RepoTypeDataset, Name: "name", Organization: "org"})
+	if got, want := string(data), `{"type":"dataset","name":"name","organization":"org"}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
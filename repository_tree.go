@@ -0,0 +1,95 @@
+package huggo
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"time"
+)
+
+// Types of the entries of a repository tree.
+const (
+	RepoTreeEntryFile      = "file"
+	RepoTreeEntryDirectory = "directory"
+)
+
+// RepoTreeEntry is a file or directory of a repository tree.
+type RepoTreeEntry struct {
+	// Type is either RepoTreeEntryFile or RepoTreeEntryDirectory.
+	Type string `json:"type"`
+	// OID is the git blob hash of files and the git tree hash of directories.
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+	Path string `json:"path"`
+	// LFS is set when the file is stored with Git LFS.
+	LFS *LFSInfo `json:"lfs,omitempty"`
+	// LastCommit is the last commit that modified the entry. It is only set when the listing is expanded.
+	LastCommit *RepoCommitInfo `json:"lastCommit,omitempty"`
+}
+
+// IsDir reports whether the entry is a directory.
+func (e RepoTreeEntry) IsDir() bool {
+	return e.Type == RepoTreeEntryDirectory
+}
+
+type RepoCommitInfo struct {
+	ID    string    `json:"id"`
+	Title string    `json:"title"`
+	Date  time.Time `json:"date"`
+}
+
+// ListRepoTree iterates over the files and directories under path in a repository at a revision, fetching them
+// page by page. An empty path lists the root of the repository and an empty revision lists DefaultRevision.
+// Subdirectories are listed too when recursive is set, and the last commit of every entry is fetched when
+// expand is set, which makes the listing slower.
+func (r *Repository) ListRepoTree(ctx context.Context, repoType RepoType, id string, revision string, path string, recursive bool, expand bool) iter.Seq2[RepoTreeEntry, error] {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	firstPage := fmt.Sprintf("%s/%s/%s/tree/%s", r.httpClient.baseURL, repoType.apiPath(), id, url.PathEscape(revision))
+	if path != "" {
+		firstPage += "/" + escapePath(path)
+	}
+	params := url.Values{}
+	if recursive {
+		params.Set("recursive", "true")
+	}
+	if expand {
+		params.Set("expand", "true")
+	}
+	if len(params) > 0 {
+		firstPage += "?" + params.Encode()
+	}
+
+	return func(yield func(RepoTreeEntry, error) bool) {
+		for next := firstPage; next != ""; {
+			var page []RepoTreeEntry
+			var err error
+			next, err = r.httpClient.getPage(ctx, next, &page)
+			if err != nil {
+				yield(RepoTreeEntry{}, fmt.Errorf("failed to list repository tree: %w", err))
+				return
+			}
+			for _, entry := range page {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// listRepoFiles lists every file of a repository at a revision.
+func (r *Repository) listRepoFiles(ctx context.Context, repoType RepoType, id string, revision string) ([]RepoTreeEntry, error) {
+	var files []RepoTreeEntry
+	for entry, err := range r.ListRepoTree(ctx, repoType, id, revision, "", true, false) {
+		if err != nil {
+			return nil, err
+		}
+		if !entry.IsDir() {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,57 @@
+package huggo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRepository_ListRepoTree(t *testing.T) {
+	pages := map[string][]RepoTreeEntry{
+		"": {
+			{Type: RepoTreeEntryDirectory, Path: "onnx", OID: "tree1"},
+			{Type: RepoTreeEntryFile, Path: "config.json", OID: "blob1", Size: 10},
+		},
+		"2": {
+			{Type: RepoTreeEntryFile, Path: "onnx/model.onnx", OID: "blob2", Size: 100, LFS: &LFSInfo{SHA256: "sha", Size: 100, PointerSize: 130}},
+		},
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/models/user/model/tree/refs%2Fpr%2F1" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("recursive") != "true" {
+			t.Errorf("Expected recursive listing")
+		}
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "" {
+			w.Header().Set("Link", "<"+server.URL+r.URL.EscapedPath()+"?recursive=true&cursor=2>; rel=\"next\"")
+		}
+		_ = json.NewEncoder(w).Encode(pages[cursor])
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	var entries []RepoTreeEntry
+	for entry, err := range repository.ListRepoTree(context.Background(), RepoTypeModel, "user/model", "refs/pr/1", "", true, false) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if !entries[0].IsDir() || entries[1].IsDir() {
+		t.Errorf("Unexpected entry types: %+v", entries)
+	}
+	if entries[2].LFS == nil || entries[2].LFS.PointerSize != 130 {
+		t.Errorf("Unexpected LFS info: %+v", entries[2].LFS)
+	}
+}
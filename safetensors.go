@@ -0,0 +1,214 @@
+package huggo
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+const (
+	// SafetensorsSingleFile is the name of the weights file of models stored in a single safetensors file.
+	SafetensorsSingleFile = "model.safetensors"
+	// SafetensorsIndexFile is the name of the index mapping the tensors of sharded models to their files.
+	SafetensorsIndexFile = "model.safetensors.index.json"
+	// maxSafetensorsHeaderSize bounds the size of the headers read, as the format does not limit it.
+	maxSafetensorsHeaderSize = 25_000_000
+)
+
+// GetSafetensorsMetadata fetches the tensors of a model stored in safetensors files without downloading its
+// weights. Only the header of each file is read with Range requests, following SafetensorsIndexFile for
+// sharded models.
+func (r *Repository) GetSafetensorsMetadata(ctx context.Context, id string, revision string) (*SafetensorsRepoMetadata, error) {
+	metadata, err := r.GetFileMetadata(ctx, RepoTypeModel, id, revision, SafetensorsSingleFile)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return r.getShardedSafetensorsMetadata(ctx, id, revision)
+	}
+	if err != nil {
+		return nil, err
+	}
+	file, err := r.getSafetensorsFileMetadata(ctx, id, metadata.CommitHash, SafetensorsSingleFile)
+	if err != nil {
+		return nil, err
+	}
+	weightMap := make(map[string]string, len(file.Tensors))
+	for name := range file.Tensors {
+		weightMap[name] = SafetensorsSingleFile
+	}
+	return newSafetensorsRepoMetadata(metadata.CommitHash, nil, weightMap, map[string]*SafetensorsFileMetadata{
+		SafetensorsSingleFile: file,
+	}), nil
+}
+
+// getShardedSafetensorsMetadata reads the headers of the files listed by the safetensors index of a model.
+func (r *Repository) getShardedSafetensorsMetadata(ctx context.Context, id string, revision string) (*SafetensorsRepoMetadata, error) {
+	metadata, err := r.GetFileMetadata(ctx, RepoTypeModel, id, revision, SafetensorsIndexFile)
+	if err != nil {
+		return nil, fmt.Errorf("model %s has no safetensors weights: %w", id, err)
+	}
+	var index struct {
+		Metadata  map[string]any    `json:"metadata"`
+		WeightMap map[string]string `json:"weight_map"`
+	}
+	rawURL := r.httpClient.resolveURL(RepoTypeModel, id, metadata.CommitHash, SafetensorsIndexFile)
+	if err := r.httpClient.getURL(ctx, rawURL, &index); err != nil {
+		return nil, fmt.Errorf("failed to get safetensors index: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	filenames := slices.Compact(slices.Sorted(maps.Values(index.WeightMap)))
+	files := make([]*SafetensorsFileMetadata, len(filenames))
+	errs := make([]error, len(filenames))
+	sem := make(chan struct{}, DefaultMaxWorkers)
+	var wg sync.WaitGroup
+	for i, filename := range filenames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			files[i], errs[i] = r.getSafetensorsFileMetadata(ctx, id, metadata.CommitHash, filename)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	byFile := make(map[string]*SafetensorsFileMetadata, len(files))
+	for i, file := range files {
+		byFile[filenames[i]] = file
+	}
+	return newSafetensorsRepoMetadata(metadata.CommitHash, index.Metadata, index.WeightMap, byFile), nil
+}
+
+// getSafetensorsFileMetadata reads the header of a safetensors file of a model at a commit.
+func (r *Repository) getSafetensorsFileMetadata(ctx context.Context, id string, commit string, filename string) (*SafetensorsFileMetadata, error) {
+	f, err := r.OpenRemoteFile(ctx, RepoTypeModel, id, commit, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	metadata, err := ReadSafetensorsMetadata(f, f.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read safetensors header of %s: %w", filename, err)
+	}
+	return metadata, nil
+}
+
+// ReadSafetensorsMetadata reads the header of a safetensors file of the given size: an 8-byte little-endian
+// length followed by a JSON object describing each tensor and, optionally, free-form "__metadata__".
+func ReadSafetensorsMetadata(r io.ReaderAt, size int64) (*SafetensorsFileMetadata, error) {
+	var prefix [8]byte
+	if _, err := r.ReadAt(prefix[:], 0); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint64(prefix[:])
+	if length > maxSafetensorsHeaderSize || int64(length) > size-8 {
+		return nil, fmt.Errorf("invalid header size %d", length)
+	}
+	header := make([]byte, length)
+	if _, err := r.ReadAt(header, 8); err != nil {
+		return nil, err
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(header, &entries); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	metadata := &SafetensorsFileMetadata{
+		Tensors:        make(map[string]TensorInfo, len(entries)),
+		ParameterCount: make(map[string]int64),
+	}
+	for name, entry := range entries {
+		if name == "__metadata__" {
+			if err := json.Unmarshal(entry, &metadata.Metadata); err != nil {
+				return nil, fmt.Errorf("invalid header metadata: %w", err)
+			}
+			continue
+		}
+		var tensor TensorInfo
+		if err := json.Unmarshal(entry, &tensor); err != nil {
+			return nil, fmt.Errorf("invalid tensor %q: %w", name, err)
+		}
+		metadata.Tensors[name] = tensor
+		metadata.ParameterCount[tensor.DType] += tensor.ParameterCount()
+	}
+	return metadata, nil
+}
+
+func newSafetensorsRepoMetadata(commit string, metadata map[string]any, weightMap map[string]string, files map[string]*SafetensorsFileMetadata) *SafetensorsRepoMetadata {
+	repoMetadata := &SafetensorsRepoMetadata{
+		CommitHash:     commit,
+		Metadata:       metadata,
+		Sharded:        len(files) > 1 || files[SafetensorsSingleFile] == nil,
+		WeightMap:      weightMap,
+		Files:          files,
+		ParameterCount: make(map[string]int64),
+	}
+	for _, file := range files {
+		for dtype, count := range file.ParameterCount {
+			repoMetadata.ParameterCount[dtype] += count
+		}
+	}
+	return repoMetadata
+}
+
+// SafetensorsRepoMetadata describes the safetensors weights of a model.
+type SafetensorsRepoMetadata struct {
+	// CommitHash is the commit the files were read at.
+	CommitHash string
+	// Metadata is the metadata of the index of sharded models, such as their total size.
+	Metadata map[string]any
+	Sharded  bool
+	// WeightMap maps the name of each tensor to the file holding it.
+	WeightMap map[string]string
+	// Files holds the header of each file by name.
+	Files map[string]*SafetensorsFileMetadata
+	// ParameterCount is the number of parameters of the model by dtype.
+	ParameterCount map[string]int64
+}
+
+// TotalParameters returns the number of parameters of the model across dtypes.
+func (m *SafetensorsRepoMetadata) TotalParameters() int64 {
+	var total int64
+	for _, count := range m.ParameterCount {
+		total += count
+	}
+	return total
+}
+
+// SafetensorsFileMetadata is the header of a safetensors file.
+type SafetensorsFileMetadata struct {
+	Metadata map[string]string
+	Tensors  map[string]TensorInfo
+	// ParameterCount is the number of parameters of the file by dtype.
+	ParameterCount map[string]int64
+}
+
+type TensorInfo struct {
+	DType string  `json:"dtype"`
+	Shape []int64 `json:"shape"`
+	// DataOffsets are the start and end offsets of the tensor data, relative to the end of the header.
+	DataOffsets [2]int64 `json:"data_offsets"`
+}
+
+// ParameterCount returns the number of elements of the tensor.
+func (t TensorInfo) ParameterCount() int64 {
+	count := int64(1)
+	for _, dim := range t.Shape {
+		count *= dim
+	}
+	return count
+}
@@ -0,0 +1,99 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSafetensorsFile builds a safetensors file holding the given tensors, with zeroed data.
+func newSafetensorsFile(t *testing.T, tensors map[string]TensorInfo) []byte {
+	t.Helper()
+	header := map[string]any{"__metadata__": map[string]string{"format": "pt"}}
+	var dataSize int64
+	for name, tensor := range tensors {
+		header[name] = tensor
+		dataSize = max(dataSize, tensor.DataOffsets[1])
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(data)))
+	buf.Write(data)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+func TestRepository_GetSafetensorsMetadata(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	files := map[string][]byte{
+		"model-00001-of-00002.safetensors": newSafetensorsFile(t, map[string]TensorInfo{
+			"embed.weight": {DType: "BF16", Shape: []int64{10, 4}, DataOffsets: [2]int64{0, 80}},
+		}),
+		"model-00002-of-00002.safetensors": newSafetensorsFile(t, map[string]TensorInfo{
+			"lm_head.weight": {DType: "BF16", Shape: []int64{10, 4}, DataOffsets: [2]int64{0, 80}},
+			"norm.weight":    {DType: "F32", Shape: []int64{4}, DataOffsets: [2]int64{80, 96}},
+		}),
+		SafetensorsIndexFile: []byte(`{"metadata":{"total_size":176},"weight_map":{` +
+			`"embed.weight":"model-00001-of-00002.safetensors",` +
+			`"lm_head.weight":"model-00002-of-00002.safetensors",` +
+			`"norm.weight":"model-00002-of-00002.safetensors"}}`),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/user/model/resolve/"
+		revision, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		content, ok := files[name]
+		if !ok || (revision != "main" && revision != commit) {
+			w.Header().Set("X-Error-Code", "EntryNotFound")
+			http.NotFound(w, r)
+			return
+		}
+		sum := sha256.Sum256(content)
+		w.Header().Set("X-Repo-Commit", commit)
+		w.Header().Set("X-Linked-Etag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("X-Linked-Size", fmt.Sprint(len(content)))
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+
+	metadata, err := repository.GetSafetensorsMetadata(context.Background(), "user/model", "main")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !metadata.Sharded || metadata.CommitHash != commit || len(metadata.Files) != 2 {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+	if metadata.ParameterCount["BF16"] != 80 || metadata.ParameterCount["F32"] != 4 || metadata.TotalParameters() != 84 {
+		t.Errorf("Unexpected parameter count %v", metadata.ParameterCount)
+	}
+	shard := metadata.Files["model-00002-of-00002.safetensors"]
+	if shard == nil || shard.Metadata["format"] != "pt" || len(shard.Tensors["norm.weight"].Shape) != 1 {
+		t.Errorf("Unexpected shard %+v", shard)
+	}
+	if metadata.WeightMap["embed.weight"] != "model-00001-of-00002.safetensors" {
+		t.Errorf("Unexpected weight map %v", metadata.WeightMap)
+	}
+}
+
+func TestReadSafetensorsMetadata_InvalidHeader(t *testing.T) {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(1<<40))
+	if _, err := ReadSafetensorsMetadata(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Errorf("Expected error for oversized header")
+	}
+}
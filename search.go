@@ -1,7 +1,12 @@
 package huggo
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -56,7 +61,17 @@ func (s *Search) GetDataset(id string) (*Dataset, error) {
 	return &dataset, nil
 }
 
-// GetMetrics fetches metrics
+// GetModelsTags fetches the tags available on models, grouped by type.
+func (s *Search) GetModelsTags() (*ModelTags, error) {
+	var tags ModelTags
+	err := s.httpClient.Get("/models-tags-by-type", &tags)
+	if err != nil {
+		return nil, err
+	}
+	return &tags, nil
+}
+
+// GetDatasetsTags fetches the tags available on datasets, grouped by type.
 func (s *Search) GetDatasetsTags() (*DatasetTags, error) {
 	var tags DatasetTags
 	err := s.httpClient.Get("/datasets-tags-by-type", &tags)
@@ -77,9 +92,14 @@ func (s *Search) GetSpaces() ([]Space, error) {
 }
 
 // GetSpaceByRepository fetches spaces associated to repository.
-func (s *Search) GetSpacesByRepository(repositoryID string) (*Space, error) {
+// When expand properties are given (e.g. "runtime"), only those properties are returned.
+func (s *Search) GetSpacesByRepository(repositoryID string, expand ...string) (*Space, error) {
 	var space Space
 	path := fmt.Sprintf("/spaces/%s", repositoryID)
+	if len(expand) > 0 {
+		params := url.Values{"expand[]": expand}
+		path += "?" + params.Encode()
+	}
 	err := s.httpClient.Get(path, &space)
 	if err != nil {
 		return nil, err
@@ -87,6 +107,17 @@ func (s *Search) GetSpacesByRepository(repositoryID string) (*Space, error) {
 	return &space, nil
 }
 
+// GetSpaceRuntime fetches the runtime information of a space: its stage, hardware and storage.
+func (s *Search) GetSpaceRuntime(id string) (*SpaceRuntime, error) {
+	var runtime SpaceRuntime
+	path := fmt.Sprintf("/spaces/%s/runtime", id)
+	err := s.httpClient.Get(path, &runtime)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime, nil
+}
+
 // GetMetrics fetches metrics.
 func (s *Search) GetMetrics() ([]Metric, error) {
 	var metrics []Metric
@@ -97,6 +128,137 @@ func (s *Search) GetMetrics() ([]Metric, error) {
 	return metrics, nil
 }
 
+// QuickSearchType is a kind of entity returned by the quick search.
+type QuickSearchType string
+
+const (
+	QuickSearchModel   QuickSearchType = "model"
+	QuickSearchDataset QuickSearchType = "dataset"
+	QuickSearchSpace   QuickSearchType = "space"
+	QuickSearchOrg     QuickSearchType = "org"
+	QuickSearchUser    QuickSearchType = "user"
+	QuickSearchPaper   QuickSearchType = "paper"
+)
+
+// Quick searches models, datasets, spaces, organizations, users and papers in a single call.
+// If no types are given, every type is searched.
+func (s *Search) Quick(query string, types ...QuickSearchType) (*QuickSearchResult, error) {
+	limits := make(map[QuickSearchType]int, len(types))
+	for _, t := range types {
+		limits[t] = 0
+	}
+	return s.QuickWithLimits(query, limits)
+}
+
+// QuickWithLimits searches the given types like Quick, keeping at most limits[type] hits for each type.
+//
+// The quick search endpoint takes a single limit shared by every type, so the largest of the limits is requested
+// and the hits of each type are then truncated to its own limit. A limit of zero sets no limit of its own: the
+// hits of its type are capped by the largest limit of the other types, or by the server's default when every
+// limit is zero.
+func (s *Search) QuickWithLimits(query string, limits map[QuickSearchType]int) (*QuickSearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	maxLimit := 0
+	for _, t := range slices.Sorted(maps.Keys(limits)) {
+		params.Add("type", string(t))
+		maxLimit = max(maxLimit, limits[t])
+	}
+	if maxLimit > 0 {
+		params.Set("limit", strconv.Itoa(maxLimit))
+	}
+
+	var result QuickSearchResult
+	err := s.httpClient.Get("/quicksearch?"+params.Encode(), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quick search: %w", err)
+	}
+	for t, limit := range limits {
+		hits := result.hits(t)
+		if limit > 0 && hits != nil && len(*hits) > limit {
+			*hits = (*hits)[:limit]
+		}
+	}
+	return &result, nil
+}
+
+// QuickSearchResult holds the ranked hits of a quick search, grouped by type.
+type QuickSearchResult struct {
+	Query         string           `json:"q"`
+	Models        []QuickSearchHit `json:"models"`
+	ModelsCount   int64            `json:"modelsCount"`
+	Datasets      []QuickSearchHit `json:"datasets"`
+	DatasetsCount int64            `json:"datasetsCount"`
+	Spaces        []QuickSearchHit `json:"spaces"`
+	SpacesCount   int64            `json:"spacesCount"`
+	Orgs          []QuickSearchHit `json:"orgs"`
+	Users         []QuickSearchHit `json:"users"`
+	Papers        []QuickSearchHit `json:"papers"`
+}
+
+// Hits returns every hit of the result, ordered by type then rank.
+func (r *QuickSearchResult) Hits() []QuickSearchHit {
+	var hits []QuickSearchHit
+	for _, group := range [][]QuickSearchHit{r.Models, r.Datasets, r.Spaces, r.Orgs, r.Users, r.Papers} {
+		hits = append(hits, group...)
+	}
+	return hits
+}
+
+// UnmarshalJSON decodes a quick search result, setting the type and rank of each hit.
+func (r *QuickSearchResult) UnmarshalJSON(data []byte) error {
+	type result QuickSearchResult
+	var res result
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	*r = QuickSearchResult(res)
+	for _, t := range []QuickSearchType{QuickSearchModel, QuickSearchDataset, QuickSearchSpace, QuickSearchOrg, QuickSearchUser, QuickSearchPaper} {
+		hits := *r.hits(t)
+		for i := range hits {
+			hits[i].Type = t
+			hits[i].Rank = i + 1
+		}
+	}
+	return nil
+}
+
+func (r *QuickSearchResult) hits(t QuickSearchType) *[]QuickSearchHit {
+	switch t {
+	case QuickSearchModel:
+		return &r.Models
+	case QuickSearchDataset:
+		return &r.Datasets
+	case QuickSearchSpace:
+		return &r.Spaces
+	case QuickSearchOrg:
+		return &r.Orgs
+	case QuickSearchUser:
+		return &r.Users
+	case QuickSearchPaper:
+		return &r.Papers
+	}
+	return nil
+}
+
+// QuickSearchHit is a single entity matched by a quick search.
+// Repositories and papers are identified by ID, organizations and users by Name.
+type QuickSearchHit struct {
+	// Type is the kind of entity that was matched.
+	Type QuickSearchType `json:"-"`
+	// Rank is the 1-based position of the hit among the hits of the same type.
+	Rank int `json:"-"`
+
+	ObjectID  string `json:"_id,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Fullname  string `json:"fullname,omitempty"`
+	Title     string `json:"title,omitempty"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
+	Private   bool   `json:"private,omitempty"`
+	Upvotes   int64  `json:"upvotes,omitempty"`
+}
+
 type Token struct {
 	Type       string `json:"__type"`
 	Content    string `json:"content"`
@@ -122,10 +284,23 @@ type ModelConfig struct {
 		QuantMethod string `json:"quant_method,omitempty"`
 	} `json:"quantization_config,omitempty"`
 	TokenizerConfig TokenizerConfig `json:"tokenizer_config,omitempty"`
+
+	// The fields below are only set by the config.json file of transformers models (see GetModelConfig).
+	TorchDType            string `json:"torch_dtype,omitempty"`
+	HiddenSize            int    `json:"hidden_size,omitempty"`
+	IntermediateSize      int    `json:"intermediate_size,omitempty"`
+	NumHiddenLayers       int    `json:"num_hidden_layers,omitempty"`
+	NumAttentionHeads     int    `json:"num_attention_heads,omitempty"`
+	NumKeyValueHeads      int    `json:"num_key_value_heads,omitempty"`
+	HeadDim               int    `json:"head_dim,omitempty"`
+	MaxPositionEmbeddings int    `json:"max_position_embeddings,omitempty"`
+	VocabSize             int    `json:"vocab_size,omitempty"`
+	// TextConfig is the config of the language model of multimodal models.
+	TextConfig *ModelConfig `json:"text_config,omitempty"`
 }
 
 type Model struct {
-	ID_           string      `json:"_id"`
+	ObjectID      string      `json:"_id"`
 	ID            string      `json:"id"`
 	Author        string      `json:"author"`
 	Gated         bool        `json:"gated"`
@@ -182,10 +357,12 @@ type Config struct {
 type DataFile struct {
 	Split string `json:"split"`
 	Path  string `json:"path"`
+	// Paths holds every pattern when the data file declares a list of patterns.
+	Paths []string `json:"-"`
 }
 
 type Space struct {
-	ID_           string        `json:"_id"`
+	ObjectID      string        `json:"_id"`
 	ID            string        `json:"id"`
 	Author        string        `json:"author"`
 	CardData      SpaceCardData `json:"cardData"`
@@ -199,6 +376,51 @@ type Space struct {
 	Tags          []string      `json:"tags"`
 	CreatedAt     string        `json:"createdAt"`
 	Siblings      []Sibling     `json:"siblings"`
+	Runtime       *SpaceRuntime `json:"runtime,omitempty"`
+}
+
+// SpaceStage is the lifecycle stage of a space.
+type SpaceStage string
+
+const (
+	SpaceStageNoAppFile      SpaceStage = "NO_APP_FILE"
+	SpaceStageConfigError    SpaceStage = "CONFIG_ERROR"
+	SpaceStageBuilding       SpaceStage = "BUILDING"
+	SpaceStageBuildError     SpaceStage = "BUILD_ERROR"
+	SpaceStageRunning        SpaceStage = "RUNNING"
+	SpaceStageRunningBuild   SpaceStage = "RUNNING_BUILDING"
+	SpaceStageRuntimeError   SpaceStage = "RUNTIME_ERROR"
+	SpaceStageDeleting       SpaceStage = "DELETING"
+	SpaceStageStopped        SpaceStage = "STOPPED"
+	SpaceStagePaused         SpaceStage = "PAUSED"
+	SpaceStageSleeping       SpaceStage = "SLEEPING"
+	SpaceStageAppStarting    SpaceStage = "APP_STARTING"
+	SpaceStageRunningStartup SpaceStage = "RUNNING_APP_STARTING"
+)
+
+type SpaceRuntime struct {
+	Stage    SpaceStage    `json:"stage"`
+	Hardware SpaceHardware `json:"hardware"`
+	// Storage is the persistent storage tier of the space, e.g. "small", or nil if it has none.
+	Storage *string `json:"storage,omitempty"`
+	// SleepTime is the number of seconds of inactivity after which the space goes to sleep, or nil if it never sleeps.
+	SleepTime    *int64         `json:"gcTimeout,omitempty"`
+	ErrorMessage *string        `json:"errorMessage,omitempty"`
+	Replicas     *SpaceReplicas `json:"replicas,omitempty"`
+	DevMode      bool           `json:"devMode,omitempty"`
+	SDKVersion   string         `json:"sdkVersion,omitempty"`
+}
+
+type SpaceHardware struct {
+	// Current is the hardware the space runs on, or nil if it is not running.
+	Current *string `json:"current"`
+	// Requested is the hardware requested for the space, e.g. "cpu-basic" or "t4-small".
+	Requested *string `json:"requested"`
+}
+
+type SpaceReplicas struct {
+	Current   int64 `json:"current"`
+	Requested int64 `json:"requested"`
 }
 
 type SpaceCardData struct {
@@ -214,14 +436,123 @@ type SpaceCardData struct {
 	ShortDescription *string `json:"short_description,omitempty"`
 }
 
+// Tag types as returned by the tags-by-type endpoints.
+const (
+	TagTypePipelineTag    = "pipeline_tag"
+	TagTypeLibrary        = "library"
+	TagTypeDataset        = "dataset"
+	TagTypeLanguage       = "language"
+	TagTypeLicense        = "license"
+	TagTypeRegion         = "region"
+	TagTypeOther          = "other"
+	TagTypeTaskCategories = "task_categories"
+	TagTypeTaskIDs        = "task_ids"
+	TagTypeSizeCategories = "size_categories"
+	TagTypeModality       = "modality"
+	TagTypeFormat         = "format"
+	TagTypeBenchmark      = "benchmark"
+)
+
+type Tag struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Label   string `json:"label"`
+	SubType string `json:"subType,omitempty"`
+}
+
+// Library is a tag of type "library".
+type Library = Tag
+
+// ModelTags holds the tags available on models, grouped by type.
+type ModelTags struct {
+	PipelineTag []Tag `json:"pipeline_tag"`
+	Library     []Tag `json:"library"`
+	Dataset     []Tag `json:"dataset"`
+	Language    []Tag `json:"language"`
+	License     []Tag `json:"license"`
+	Region      []Tag `json:"region"`
+	Other       []Tag `json:"other"`
+}
+
+// All returns every model tag regardless of its type.
+func (t *ModelTags) All() []Tag {
+	return joinTags(t.PipelineTag, t.Library, t.Dataset, t.Language, t.License, t.Region, t.Other)
+}
+
+// Find looks up a model tag by its ID.
+func (t *ModelTags) Find(id string) (Tag, bool) {
+	return findTag(id, t.All())
+}
+
+// Label returns the label of a model tag, or an empty string if the tag is unknown.
+func (t *ModelTags) Label(id string) string {
+	tag, _ := t.Find(id)
+	return tag.Label
+}
+
+// TypeOf returns the type of a model tag, or an empty string if the tag is unknown.
+func (t *ModelTags) TypeOf(id string) string {
+	tag, _ := t.Find(id)
+	return tag.Type
+}
+
+// DatasetTags holds the tags available on datasets, grouped by type.
 type DatasetTags struct {
-	Library []Library `json:"library"`
+	PipelineTag    []Tag `json:"pipeline_tag"`
+	Library        []Tag `json:"library"`
+	Dataset        []Tag `json:"dataset"`
+	Language       []Tag `json:"language"`
+	License        []Tag `json:"license"`
+	Region         []Tag `json:"region"`
+	Other          []Tag `json:"other"`
+	TaskCategories []Tag `json:"task_categories"`
+	TaskIDs        []Tag `json:"task_ids"`
+	SizeCategories []Tag `json:"size_categories"`
+	Modality       []Tag `json:"modality"`
+	Format         []Tag `json:"format"`
+	Benchmark      []Tag `json:"benchmark"`
 }
 
-type Library struct {
-	ID    string `json:"id"`
-	Type  string `json:"type"`
-	Label string `json:"label"`
+// All returns every dataset tag regardless of its type.
+func (t *DatasetTags) All() []Tag {
+	return joinTags(
+		t.PipelineTag, t.Library, t.Dataset, t.Language, t.License, t.Region, t.Other,
+		t.TaskCategories, t.TaskIDs, t.SizeCategories, t.Modality, t.Format, t.Benchmark,
+	)
+}
+
+// Find looks up a dataset tag by its ID.
+func (t *DatasetTags) Find(id string) (Tag, bool) {
+	return findTag(id, t.All())
+}
+
+// Label returns the label of a dataset tag, or an empty string if the tag is unknown.
+func (t *DatasetTags) Label(id string) string {
+	tag, _ := t.Find(id)
+	return tag.Label
+}
+
+// TypeOf returns the type of a dataset tag, or an empty string if the tag is unknown.
+func (t *DatasetTags) TypeOf(id string) string {
+	tag, _ := t.Find(id)
+	return tag.Type
+}
+
+func joinTags(groups ...[]Tag) []Tag {
+	var tags []Tag
+	for _, group := range groups {
+		tags = append(tags, group...)
+	}
+	return tags
+}
+
+func findTag(id string, tags []Tag) (Tag, bool) {
+	for _, tag := range tags {
+		if tag.ID == id {
+			return tag, true
+		}
+	}
+	return Tag{}, false
 }
 
 type Sibling struct {
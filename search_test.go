@@ -0,0 +1,241 @@
+package huggo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestModelTags_Find(t *testing.T) {
+	data := `{
+		"pipeline_tag": [{"id":"text-generation","label":"Text Generation","type":"pipeline_tag","subType":"nlp"}],
+		"library": [{"id":"transformers","label":"Transformers","type":"library"}],
+		"license": [{"id":"license:mit","label":"mit","type":"license"}]
+	}`
+	var tags ModelTags
+	if err := json.Unmarshal([]byte(data), &tags); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		id        string
+		wantFound bool
+		wantLabel string
+		wantType  string
+	}{
+		{id: "text-generation", wantFound: true, wantLabel: "Text Generation", wantType: TagTypePipelineTag},
+		{id: "transformers", wantFound: true, wantLabel: "Transformers", wantType: TagTypeLibrary},
+		{id: "license:mit", wantFound: true, wantLabel: "mit", wantType: TagTypeLicense},
+		{id: "unknown", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, found := tags.Find(tt.id)
+			if found != tt.wantFound {
+				t.Errorf("Find() found = %v, want %v", found, tt.wantFound)
+			}
+			if got := tags.Label(tt.id); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+			if got := tags.TypeOf(tt.id); got != tt.wantType {
+				t.Errorf("TypeOf() = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestDatasetTags_All(t *testing.T) {
+	data := `{
+		"library": [{"id":"library:datasets","label":"Datasets","type":"library"}],
+		"task_categories": [{"id":"task_categories:translation","label":"Translation","type":"task_categories"}],
+		"modality": [{"id":"modality:text","label":"Text","type":"modality"}]
+	}`
+	var tags DatasetTags
+	if err := json.Unmarshal([]byte(data), &tags); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := len(tags.All()); got != 3 {
+		t.Errorf("Expected 3 tags, got %d", got)
+	}
+	if got := tags.TypeOf("modality:text"); got != TagTypeModality {
+		t.Errorf("Expected type %s, got %s", TagTypeModality, got)
+	}
+}
+
+func TestQuickSearchResult_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"q": "llama",
+		"models": [{"id":"meta-llama/Llama-2-7b"},{"id":"meta-llama/Llama-2-13b"}],
+		"modelsCount": 2,
+		"orgs": [{"name":"meta-llama","fullname":"Meta Llama"}]
+	}`
+	var result QuickSearchResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Query != "llama" {
+		t.Errorf("Expected query llama, got %s", result.Query)
+	}
+	hits := result.Hits()
+	if len(hits) != 3 {
+		t.Fatalf("Expected 3 hits, got %d", len(hits))
+	}
+	if hits[1].Type != QuickSearchModel || hits[1].Rank != 2 || hits[1].ID != "meta-llama/Llama-2-13b" {
+		t.Errorf("Unexpected second hit: %+v", hits[1])
+	}
+	if hits[2].Type != QuickSearchOrg || hits[2].Rank != 1 || hits[2].Name != "meta-llama" {
+		t.Errorf("Unexpected third hit: %+v", hits[2])
+	}
+}
+
+func TestSearch_QuickWithLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/quicksearch" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if got := query.Get("q"); got != "llama" {
+			t.Errorf("Expected q llama, got %s", got)
+		}
+		if got := query["type"]; !slices.Equal(got, []string{"dataset", "model", "org"}) {
+			t.Errorf("Unexpected types %v", got)
+		}
+		if got := query.Get("limit"); got != "3" {
+			t.Errorf("Expected limit 3, got %s", got)
+		}
+		_, _ = w.Write([]byte(`{
+			"q": "llama",
+			"models": [{"id": "a/m1"}, {"id": "a/m2"}, {"id": "a/m3"}],
+			"datasets": [{"id": "a/d1"}, {"id": "a/d2"}, {"id": "a/d3"}],
+			"orgs": [{"name": "a"}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	limits := map[QuickSearchType]int{QuickSearchModel: 3, QuickSearchDataset: 1, QuickSearchOrg: 0}
+	result, err := NewSearch(client).QuickWithLimits("llama", limits)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Models) != 3 || len(result.Datasets) != 1 || len(result.Orgs) != 1 {
+		t.Errorf("Unexpected hits %+v", result)
+	}
+}
+
+func TestSearch_GetSpacesByRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		expand     []string
+		wantExpand []string
+	}{
+		{name: "without expand"},
+		{name: "with expand", expand: []string{"runtime", "likes"}, wantExpand: []string{"runtime", "likes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/spaces/user/space" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				if got := r.URL.Query()["expand[]"]; !slices.Equal(got, tt.wantExpand) {
+					t.Errorf("Expected expand %v, got %v", tt.wantExpand, got)
+				}
+				_, _ = w.Write([]byte(`{"id": "user/space", "likes": 3, "runtime": {"stage": "SLEEPING", "hardware": {"current": null, "requested": "cpu-basic"}}}`))
+			}))
+			defer server.Close()
+
+			client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+			space, err := NewSearch(client).GetSpacesByRepository("user/space", tt.expand...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if space.ID != "user/space" || space.Runtime == nil || space.Runtime.Stage != SpaceStageSleeping {
+				t.Errorf("Unexpected space %+v", space)
+			}
+		})
+	}
+}
+
+func TestSearch_GetSpaceRuntime(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantStage     SpaceStage
+		wantCurrent   *string
+		wantRequested string
+		wantSleepTime *int64
+		wantStorage   *string
+	}{
+		{
+			name: "running",
+			body: `{"stage": "RUNNING", "hardware": {"current": "t4-small", "requested": "t4-small"},
+				"storage": "small", "gcTimeout": 3600, "replicas": {"current": 1, "requested": 1}, "sdkVersion": "4.0.0"}`,
+			wantStage:     SpaceStageRunning,
+			wantCurrent:   ptr("t4-small"),
+			wantRequested: "t4-small",
+			wantSleepTime: ptr(int64(3600)),
+			wantStorage:   ptr("small"),
+		},
+		{
+			name:          "paused",
+			body:          `{"stage": "PAUSED", "hardware": {"current": null, "requested": "cpu-basic"}, "storage": null, "gcTimeout": null}`,
+			wantStage:     SpaceStagePaused,
+			wantRequested: "cpu-basic",
+		},
+		{
+			name:          "build error",
+			body:          `{"stage": "BUILD_ERROR", "hardware": {"current": null, "requested": "a10g-large"}, "errorMessage": "failed"}`,
+			wantStage:     SpaceStageBuildError,
+			wantRequested: "a10g-large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/spaces/user/space/runtime" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+			runtime, err := NewSearch(client).GetSpaceRuntime("user/space")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if runtime.Stage != tt.wantStage {
+				t.Errorf("Expected stage %s, got %s", tt.wantStage, runtime.Stage)
+			}
+			if !equalPtr(runtime.Hardware.Current, tt.wantCurrent) {
+				t.Errorf("Unexpected current hardware %v", runtime.Hardware.Current)
+			}
+			if runtime.Hardware.Requested == nil || *runtime.Hardware.Requested != tt.wantRequested {
+				t.Errorf("Unexpected requested hardware %v", runtime.Hardware.Requested)
+			}
+			if !equalPtr(runtime.SleepTime, tt.wantSleepTime) {
+				t.Errorf("Unexpected sleep time %v", runtime.SleepTime)
+			}
+			if !equalPtr(runtime.Storage, tt.wantStorage) {
+				t.Errorf("Unexpected storage %v", runtime.Storage)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalPtr[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
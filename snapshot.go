@@ -0,0 +1,231 @@
+package huggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SnapshotOptions configures a snapshot download.
+type SnapshotOptions struct {
+	// Revision is the branch, tag or commit to download. It defaults to DefaultRevision.
+	Revision string
+	// AllowPatterns restricts the download to the files matching at least one of the patterns.
+	// Patterns are matched against whole repository paths in the fnmatch style: "*" matches any sequence of
+	// characters including "/", and a pattern ending with "/" matches every file below that directory.
+	AllowPatterns []string
+	// IgnorePatterns excludes the files matching any of the patterns.
+	IgnorePatterns []string
+	// MaxWorkers is the number of files downloaded concurrently. It defaults to DefaultMaxWorkers.
+	MaxWorkers int
+	// LocalDir is the directory the files are downloaded into, keeping their repository layout.
+	// When empty, the files are downloaded into the cache (see WithCacheDir).
+	LocalDir string
+}
+
+// SnapshotDownload downloads the files of a repository at a revision and returns the directory holding them:
+// the snapshot of the resolved commit in the cache, or LocalDir when set.
+//
+// The revision is resolved to a commit once, so that every file comes from the same commit.
+// Files already cached, or present in LocalDir with the expected content, are not downloaded again.
+// When downloading into the cache and the Hub cannot be reached, the snapshot cached for the revision is returned.
+func (r *Repository) SnapshotDownload(ctx context.Context, repoType RepoType, id string, opts SnapshotOptions) (string, error) {
+	revision := opts.Revision
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	c := r.httpClient.cache
+	cacheType := string(repoType)
+
+	commit, err := r.getRevisionCommit(ctx, repoType, id, revision)
+	var apiErr *APIError
+	if err != nil && opts.LocalDir == "" && !errors.As(err, &apiErr) && ctx.Err() == nil {
+		if cached, refErr := c.ReadRef(cacheType, id, revision); refErr == nil {
+			dir := c.SnapshotDir(cacheType, id, cached)
+			if _, statErr := os.Stat(dir); statErr == nil {
+				return dir, nil
+			}
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := r.listRepoFiles(ctx, repoType, id, commit)
+	if err != nil {
+		return "", err
+	}
+	var files []RepoTreeEntry
+	for _, entry := range entries {
+		if matchSnapshotPatterns(entry.Path, opts.AllowPatterns, opts.IgnorePatterns) {
+			files = append(files, entry)
+		}
+	}
+
+	dir := opts.LocalDir
+	if dir == "" {
+		dir = c.SnapshotDir(cacheType, id, commit)
+		if err := c.WriteRef(cacheType, id, revision, commit); err != nil {
+			return "", fmt.Errorf("failed to update cache ref: %w", err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := r.downloadSnapshotFiles(ctx, repoType, id, commit, files, opts); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// downloadSnapshotFiles downloads files of a commit concurrently into the cache or opts.LocalDir.
+func (r *Repository) downloadSnapshotFiles(ctx context.Context, repoType RepoType, id string, commit string, files []RepoTreeEntry, opts SnapshotOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxWorkers := opts.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers
+	}
+	errs := make([]error, len(files))
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			digest := treeDigest(file.Size, file.OID, file.LFS)
+			if opts.LocalDir == "" {
+				_, errs[i] = r.downloadBlobToCache(ctx, repoType, id, commit, file.Path, digest, nil)
+			} else {
+				p := filepath.Join(opts.LocalDir, filepath.FromSlash(file.Path))
+				if fileMatches(p, digest) {
+					return
+				}
+				rawURL := r.httpClient.resolveURL(repoType, id, commit, file.Path)
+				if err := r.httpClient.downloadToFile(ctx, rawURL, p, digest, nil); err != nil {
+					errs[i] = fmt.Errorf("failed to download %s: %w", file.Path, err)
+				}
+			}
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstError(errs)
+}
+
+// firstError returns the first error of concurrent operations, preferring errors other than the cancellation
+// the first failure caused to the other operations.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRevisionCommit resolves a revision of a repository to the commit it points to.
+func (r *Repository) getRevisionCommit(ctx context.Context, repoType RepoType, id string, revision string) (string, error) {
+	var info struct {
+		SHA string `json:"sha"`
+	}
+	rawURL := fmt.Sprintf("%s/%s/%s/revision/%s", r.httpClient.baseURL, repoType.apiPath(), id, url.PathEscape(revision))
+	if err := r.httpClient.getURL(ctx, rawURL, &info); err != nil {
+		return "", fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+	}
+	if info.SHA == "" {
+		return "", fmt.Errorf("failed to resolve revision %s: no commit returned", revision)
+	}
+	return info.SHA, nil
+}
+
+// matchSnapshotPatterns reports whether a repository path matches at least one of the allow patterns, if any,
+// and none of the ignore patterns.
+func matchSnapshotPatterns(p string, allow []string, ignore []string) bool {
+	if len(allow) > 0 && !matchAnyFilePattern(p, allow) {
+		return false
+	}
+	return !matchAnyFilePattern(p, ignore)
+}
+
+func matchAnyFilePattern(p string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchFilePattern(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilePattern reports whether name matches an fnmatch-style pattern, where "*" matches any sequence of
+// characters including "/", "?" matches any single character and "[...]" matches a set of characters.
+// A pattern ending with "/" matches every file below that directory.
+func matchFilePattern(pattern string, name string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "*"
+	}
+	re, err := regexp.Compile(translateFilePattern(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
+
+// translateFilePattern translates an fnmatch-style pattern into an anchored regular expression.
+func translateFilePattern(pattern string) string {
+	var b strings.Builder
+	b.WriteString(`^(?s:`)
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		case '[':
+			end := i + 1
+			if end < len(pattern) && pattern[end] == '!' {
+				end++
+			}
+			if end < len(pattern) && pattern[end] == ']' {
+				end++
+			}
+			for end < len(pattern) && pattern[end] != ']' {
+				end++
+			}
+			if end >= len(pattern) {
+				// An unterminated set matches a literal "[".
+				b.WriteString(`\[`)
+				continue
+			}
+			set := pattern[i+1 : end]
+			b.WriteByte('[')
+			if strings.HasPrefix(set, "!") {
+				b.WriteByte('^')
+				set = set[1:]
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `^`, `\^`).Replace(set))
+			b.WriteByte(']')
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString(`)$`)
+	return b.String()
+}
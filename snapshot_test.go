@@ -0,0 +1,131 @@
+package huggo
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMatchFilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.json", "config.json", true},
+		{"*.json", "nested/config.json", true},
+		{"*.safetensors", "model.bin", false},
+		{"onnx/", "onnx/model.onnx", true},
+		{"onnx/", "model.onnx", false},
+		{"model-?????-of-00002.safetensors", "model-00001-of-00002.safetensors", true},
+		{"[!.]*", ".gitattributes", false},
+		{"[ab].txt", "b.txt", true},
+		{"a.b", "axb", false},
+		{"[unterminated", "[unterminated", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchFilePattern(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRepository_SnapshotDownload(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	contents := map[string]string{
+		"config.json":         "{}",
+		"model.safetensors":   "weights",
+		"onnx/model.onnx":     "onnx",
+		"tokenizer/vocab.txt": "a\nb\n",
+	}
+	var tree []RepoTreeEntry
+	for name, content := range contents {
+		h := newGitBlobHash(int64(len(content)))
+		h.Write([]byte(content))
+		tree = append(tree, RepoTreeEntry{Type: "file", Path: name, Size: int64(len(content)), OID: hex.EncodeToString(h.Sum(nil))})
+	}
+
+	var downloads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/models/org/model/revision/main", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"sha": commit})
+	})
+	mux.HandleFunc("/api/models/org/model/tree/"+commit, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/org/model/resolve/"+commit+"/", func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		name := r.URL.Path[len("/org/model/resolve/"+commit+"/"):]
+		_, _ = w.Write([]byte(contents[name]))
+	})
+	server := httptest.NewServer(mux)
+
+	cacheDir := t.TempDir()
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"), WithCacheDir(cacheDir))
+	repository := NewRepository(client)
+	ctx := context.Background()
+	opts := SnapshotOptions{
+		AllowPatterns:  []string{"*.json", "*.safetensors", "tokenizer/"},
+		IgnorePatterns: []string{"*.txt"},
+		MaxWorkers:     2,
+	}
+
+	dir, err := repository.SnapshotDownload(ctx, RepoTypeModel, "org/model", opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := filepath.Join(cacheDir, "models--org--model", "snapshots", commit); dir != want {
+		t.Errorf("Expected snapshot %s, got %s", want, dir)
+	}
+	var got []string
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, p)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "config.json" || got[1] != "model.safetensors" {
+		t.Errorf("Unexpected snapshot files %v", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "model.safetensors")); string(data) != "weights" {
+		t.Errorf("Unexpected content %q", data)
+	}
+
+	// Cached files are not downloaded again.
+	if _, err := repository.SnapshotDownload(ctx, RepoTypeModel, "org/model", opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := downloads.Load(); n != 2 {
+		t.Errorf("Expected 2 downloads, got %d", n)
+	}
+
+	localDir := t.TempDir()
+	local, err := repository.SnapshotDownload(ctx, RepoTypeModel, "org/model", SnapshotOptions{LocalDir: localDir, AllowPatterns: []string{"onnx/"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(local, "onnx", "model.onnx")); local != localDir || string(data) != "onnx" {
+		t.Errorf("Unexpected local download in %s: %q", local, data)
+	}
+
+	// The cached snapshot is returned when the Hub cannot be reached.
+	server.Close()
+	offline, err := repository.SnapshotDownload(ctx, RepoTypeModel, "org/model", opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if offline != dir {
+		t.Errorf("Expected snapshot %s, got %s", dir, offline)
+	}
+}
@@ -0,0 +1,309 @@
+package huggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxFilesPerCommit is the number of operations above which UploadFolder splits an upload into several
+// commits.
+const DefaultMaxFilesPerCommit = 1000
+
+// DefaultUploadFolderMessage is the commit message of UploadFolder when none is given.
+const DefaultUploadFolderMessage = "Upload folder using huggo"
+
+// UploadFolderOptions configures a folder upload.
+type UploadFolderOptions struct {
+	// Revision is the branch to commit to. It defaults to DefaultRevision.
+	Revision string
+	// PathInRepo is the directory of the repository the folder is uploaded to. It defaults to the root.
+	PathInRepo string
+	// AllowPatterns restricts the upload to the files matching at least one of the patterns.
+	// Patterns are matched against paths relative to the folder, as in SnapshotOptions.AllowPatterns.
+	AllowPatterns []string
+	// IgnorePatterns excludes the files matching any of the patterns.
+	IgnorePatterns []string
+	// DeletePatterns deletes the files of the repository under PathInRepo that are missing from the folder and
+	// match any of the patterns, e.g. "*" to mirror the folder. Patterns are matched against paths relative to
+	// PathInRepo. The .gitattributes file is never deleted.
+	DeletePatterns []string
+	// Message is the commit message. It defaults to DefaultUploadFolderMessage.
+	Message string
+	// Description is the extended description of the commits.
+	Description string
+	// ParentCommit is the commit the revision is expected to point to. See CommitOptions.ParentCommit.
+	ParentCommit string
+	// CreatePR opens a pull request with the commits instead of committing to the revision.
+	CreatePR bool
+	// MaxFilesPerCommit is the number of operations of each commit. Larger uploads are split into several
+	// commits. It defaults to DefaultMaxFilesPerCommit.
+	MaxFilesPerCommit int
+	// MaxWorkers is the number of LFS files uploaded concurrently. It defaults to DefaultMaxWorkers.
+	MaxWorkers int
+	// Progress, if set, is called as LFS files are uploaded.
+	Progress UploadProgressFunc
+}
+
+// UploadFolder uploads the files of a local folder to a repository and returns the commits created, which are
+// none if the repository is already up to date.
+//
+// Files ignored by the .gitignore files of the folder, as well as the .git directory, are not uploaded. Files
+// whose content matches the file at the same path in the repository, by sha256 for LFS files and by git blob
+// hash otherwise, are skipped. Uploads of more than MaxFilesPerCommit operations are split into several commits,
+// each expecting the previous one as parent; with CreatePR, the later commits are pushed to the pull request
+// opened by the first one.
+func (r *Repository) UploadFolder(ctx context.Context, repoType RepoType, id string, localDir string, opts UploadFolderOptions) ([]*CommitInfo, error) {
+	if opts.Revision == "" {
+		opts.Revision = DefaultRevision
+	}
+	if opts.Message == "" {
+		opts.Message = DefaultUploadFolderMessage
+	}
+	pathInRepo := strings.Trim(opts.PathInRepo, "/")
+	localFiles, err := listLocalFiles(localDir, opts.AllowPatterns, opts.IgnorePatterns)
+	if err != nil {
+		return nil, err
+	}
+	remoteFiles, err := r.listRemoteFolder(ctx, repoType, id, opts.Revision, pathInRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []CommitOperation
+	local := make(map[string]bool, len(localFiles))
+	for _, rel := range localFiles {
+		repoPath := path.Join(pathInRepo, rel)
+		local[repoPath] = true
+		localPath := filepath.Join(localDir, filepath.FromSlash(rel))
+		if entry, ok := remoteFiles[repoPath]; ok && fileMatches(localPath, treeDigest(entry.Size, entry.OID, entry.LFS)) {
+			continue
+		}
+		operations = append(operations, CommitOperationAdd{PathInRepo: repoPath, LocalPath: localPath})
+	}
+	if len(opts.DeletePatterns) > 0 {
+		var deleted []string
+		for repoPath := range remoteFiles {
+			rel := strings.TrimPrefix(strings.TrimPrefix(repoPath, pathInRepo), "/")
+			if !local[repoPath] && repoPath != ".gitattributes" && matchAnyFilePattern(rel, opts.DeletePatterns) {
+				deleted = append(deleted, repoPath)
+			}
+		}
+		slices.Sort(deleted)
+		for _, repoPath := range deleted {
+			operations = append(operations, CommitOperationDelete{PathInRepo: repoPath})
+		}
+	}
+	if len(operations) == 0 {
+		return nil, nil
+	}
+
+	maxFiles := opts.MaxFilesPerCommit
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFilesPerCommit
+	}
+	numCommits := (len(operations) + maxFiles - 1) / maxFiles
+	revision := opts.Revision
+	commitOpts := CommitOptions{
+		Description:  opts.Description,
+		ParentCommit: opts.ParentCommit,
+		CreatePR:     opts.CreatePR,
+		MaxWorkers:   opts.MaxWorkers,
+		Progress:     opts.Progress,
+	}
+	var commits []*CommitInfo
+	for batch := range slices.Chunk(operations, maxFiles) {
+		message := opts.Message
+		if numCommits > 1 {
+			message = fmt.Sprintf("%s (part %d of %d)", opts.Message, len(commits)+1, numCommits)
+		}
+		info, err := r.CreateCommit(ctx, repoType, id, revision, batch, message, commitOpts)
+		if err != nil {
+			return commits, err
+		}
+		commits = append(commits, info)
+		commitOpts.ParentCommit = info.OID
+		if commitOpts.CreatePR {
+			revision = fmt.Sprintf("refs/pr/%d", info.PullRequestNumber)
+			commitOpts.CreatePR = false
+		}
+	}
+	return commits, nil
+}
+
+// listRemoteFolder lists the files of a repository under a directory by path. A directory missing from the
+// repository has no files.
+func (r *Repository) listRemoteFolder(ctx context.Context, repoType RepoType, id string, revision string, dir string) (map[string]RepoTreeEntry, error) {
+	files := make(map[string]RepoTreeEntry)
+	for entry, err := range r.ListRepoTree(ctx, repoType, id, revision, dir, true, false) {
+		var apiErr *APIError
+		if dir != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !entry.IsDir() {
+			files[entry.Path] = entry
+		}
+	}
+	return files, nil
+}
+
+// listLocalFiles lists the files of a folder to upload, as slash-separated paths relative to the folder.
+func listLocalFiles(localDir string, allow []string, ignore []string) ([]string, error) {
+	var rules []gitignoreRule
+	var files []string
+	err := filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == "." {
+				rel = ""
+			} else if d.Name() == ".git" || rel == ".cache/huggingface" || gitignored(rules, rel, true) {
+				return filepath.SkipDir
+			}
+			data, err := os.ReadFile(filepath.Join(p, ".gitignore"))
+			if err == nil {
+				rules = append(rules, parseGitignore(rel, string(data))...)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			// Symbolic links are uploaded as the file they point to.
+			info, err := os.Stat(p)
+			if err != nil || !info.Mode().IsRegular() {
+				return nil
+			}
+		}
+		if !gitignored(rules, rel, false) && matchSnapshotPatterns(rel, allow, ignore) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local files: %w", err)
+	}
+	return files, nil
+}
+
+// gitignoreRule is a pattern of a .gitignore file.
+type gitignoreRule struct {
+	// base is the directory of the .gitignore file, relative to the folder, or empty at its root.
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseGitignore parses the rules of the .gitignore file of the directory base.
+func parseGitignore(base string, data string) []gitignoreRule {
+	var rules []gitignoreRule
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := gitignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		re, err := regexp.Compile(translateGitignorePattern(line))
+		if err != nil || line == "" {
+			continue
+		}
+		rule.re = re
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// translateGitignorePattern translates a .gitignore pattern into an anchored regular expression matched against
+// paths relative to the .gitignore file. Patterns without "/" but at their end match at any depth, and "*" does
+// not match "/" while "**" does.
+func translateGitignorePattern(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(pattern, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			j := strings.IndexByte(pattern[i+1:], ']')
+			if j < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			set := pattern[i+1 : i+1+j]
+			if strings.HasPrefix(set, "!") {
+				set = "^" + set[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(set, `\`, `\\`) + "]")
+			i += j + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// gitignored reports whether the file or directory at the slash-separated path rel is ignored by rules, the last
+// matching rule taking precedence.
+func gitignored(rules []gitignoreRule, rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		name := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			name = rel[len(rule.base)+1:]
+		}
+		if rule.re.MatchString(name) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
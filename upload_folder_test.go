@@ -0,0 +1,232 @@
+package huggo
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newUploadServer serves the endpoints of a commit of regular files to a model repository with the given files,
+// recording the revision and NDJSON lines of every commit.
+func newUploadServer(tree []RepoTreeEntry) (*httptest.Server, *[]string, *[][]map[string]any) {
+	var revisions []string
+	var commits [][]map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/models/user/model/tree/main", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(tree)
+	})
+	mux.HandleFunc("/api/models/user/model/preupload/", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		files := make([]map[string]any, len(payload.Files))
+		for i, file := range payload.Files {
+			files[i] = map[string]any{"path": file.Path, "uploadMode": "regular"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+	})
+	mux.HandleFunc("/api/models/user/model/commit/", func(w http.ResponseWriter, r *http.Request) {
+		revisions = append(revisions, strings.TrimPrefix(r.URL.EscapedPath(), "/api/models/user/model/commit/"))
+		var lines []map[string]any
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			_ = json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+		commits = append(commits, lines)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"commitOid":      fmt.Sprintf("commit%d", len(commits)),
+			"pullRequestUrl": "https://huggingface.co/user/model/discussions/3",
+		})
+	})
+	return httptest.NewServer(mux), &revisions, &commits
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// commitPaths returns the operations of a commit as "key path".
+func commitPaths(lines []map[string]any) []string {
+	var paths []string
+	for _, line := range lines[1:] {
+		value, _ := line["value"].(map[string]any)
+		paths = append(paths, fmt.Sprintf("%s %s", line["key"], value["path"]))
+	}
+	return paths
+}
+
+func TestRepository_UploadFolder(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.txt":          "unchanged",
+		"b.txt":          "changed",
+		"model.bin":      "weights",
+		"new.txt":        "new",
+		"notes.tmp":      "scratch",
+		".gitignore":     "# Logs\n*.log\n!keep.log\nbuild/\n",
+		"debug.log":      "debug",
+		"keep.log":       "keep",
+		"build/out.bin":  "out",
+		".git/HEAD":      "ref: refs/heads/main",
+		"sub/.gitignore": "/secret.txt\n",
+		"sub/secret.txt": "secret",
+		"sub/x.txt":      "x",
+	})
+	h := newGitBlobHash(int64(len("unchanged")))
+	h.Write([]byte("unchanged"))
+	weights := sha256.Sum256([]byte("weights"))
+	tree := []RepoTreeEntry{
+		{Type: "file", Path: ".gitattributes", Size: 10, OID: "attributes"},
+		{Type: "file", Path: "a.txt", Size: 9, OID: hex.EncodeToString(h.Sum(nil))},
+		{Type: "file", Path: "b.txt", Size: 9, OID: "outdated"},
+		{Type: "file", Path: "model.bin", Size: 132, OID: "pointer", LFS: &LFSInfo{SHA256: hex.EncodeToString(weights[:]), Size: 7}},
+		{Type: "file", Path: "old.txt", Size: 3, OID: "old"},
+		{Type: "directory", Path: "sub", OID: "tree"},
+	}
+	server, revisions, commits := newUploadServer(tree)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	infos, err := repository.UploadFolder(context.Background(), RepoTypeModel, "user/model", dir, UploadFolderOptions{
+		IgnorePatterns: []string{"*.tmp"},
+		DeletePatterns: []string{"*"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].OID != "commit1" || (*revisions)[0] != "main" {
+		t.Fatalf("Unexpected commits %+v to %v", infos, *revisions)
+	}
+	header, _ := (*commits)[0][0]["value"].(map[string]any)
+	if header["summary"] != DefaultUploadFolderMessage {
+		t.Errorf("Unexpected header %v", header)
+	}
+	want := []string{
+		"file .gitignore",
+		"file b.txt",
+		"file keep.log",
+		"file new.txt",
+		"file sub/.gitignore",
+		"file sub/x.txt",
+		"deletedFile old.txt",
+	}
+	if got := commitPaths((*commits)[0]); !slices.Equal(got, want) {
+		t.Errorf("Expected operations %v, got %v", want, got)
+	}
+
+	// Without the .gitignore file, the files it ignored are uploaded too.
+	_ = os.Remove(filepath.Join(dir, "b.txt"))
+	_ = os.Remove(filepath.Join(dir, "new.txt"))
+	_ = os.RemoveAll(filepath.Join(dir, "sub"))
+	_ = os.Remove(filepath.Join(dir, ".gitignore"))
+	_ = os.Remove(filepath.Join(dir, "debug.log"))
+	_ = os.Remove(filepath.Join(dir, "keep.log"))
+	infos, err = repository.UploadFolder(context.Background(), RepoTypeModel, "user/model", dir, UploadFolderOptions{
+		AllowPatterns: []string{"*.txt", "*.bin"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(infos) != 1 || !slices.Equal(commitPaths((*commits)[1]), []string{"file build/out.bin"}) {
+		t.Errorf("Unexpected operations %v", commitPaths((*commits)[len(*commits)-1]))
+	}
+}
+
+func TestRepository_UploadFolder_Split(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+	server, revisions, commits := newUploadServer(nil)
+	defer server.Close()
+
+	client, _ := NewHttpClient("apiKey", WithBaseURL(server.URL+"/api"))
+	repository := NewRepository(client)
+	infos, err := repository.UploadFolder(context.Background(), RepoTypeModel, "user/model", dir, UploadFolderOptions{
+		PathInRepo:        "data/",
+		Message:           "Add data",
+		ParentCommit:      "abc123",
+		CreatePR:          true,
+		MaxFilesPerCommit: 2,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(infos))
+	}
+	if !slices.Equal(*revisions, []string{"main", "refs%2Fpr%2F3"}) {
+		t.Errorf("Unexpected revisions %v", *revisions)
+	}
+	tests := []struct {
+		summary      string
+		parentCommit string
+		paths        []string
+	}{
+		{"Add data (part 1 of 2)", "abc123", []string{"file data/a.txt", "file data/b.txt"}},
+		{"Add data (part 2 of 2)", "commit1", []string{"file data/c.txt"}},
+	}
+	for i, tt := range tests {
+		header, _ := (*commits)[i][0]["value"].(map[string]any)
+		if header["summary"] != tt.summary || header["parentCommit"] != tt.parentCommit {
+			t.Errorf("Unexpected header of commit %d: %v", i, header)
+		}
+		if got := commitPaths((*commits)[i]); !slices.Equal(got, tt.paths) {
+			t.Errorf("Expected operations %v, got %v", tt.paths, got)
+		}
+	}
+}
+
+func TestGitignored(t *testing.T) {
+	rules := parseGitignore("", "# comment\n*.log\n!important.log\n/root.txt\ndocs/**/*.md\ncache/\n\\#hash\n")
+	rules = append(rules, parseGitignore("sub", "local.txt\n")...)
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"a/b/debug.log", false, true},
+		{"important.log", false, false},
+		{"root.txt", false, true},
+		{"a/root.txt", false, false},
+		{"docs/guide.md", false, true},
+		{"docs/a/b/guide.md", false, true},
+		{"guide.md", false, false},
+		{"cache", true, true},
+		{"a/cache", true, true},
+		{"cache", false, false},
+		{"#hash", false, true},
+		{"sub/local.txt", false, true},
+		{"local.txt", false, false},
+		{"sub/a/local.txt", false, true},
+	}
+	for _, tt := range tests {
+		if got := gitignored(rules, tt.path, tt.isDir); got != tt.want {
+			t.Errorf("gitignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
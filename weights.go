@@ -0,0 +1,350 @@
+package huggo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// WeightFormat is a format model weights are stored in.
+type WeightFormat string
+
+const (
+	WeightFormatSafetensors WeightFormat = "safetensors"
+	WeightFormatPyTorch     WeightFormat = "pytorch"
+	WeightFormatGGUF        WeightFormat = "gguf"
+	WeightFormatONNX        WeightFormat = "onnx"
+)
+
+var (
+	// shardSuffixRegexp matches the suffix of the files of sharded weights, e.g. "-00001-of-00004".
+	shardSuffixRegexp = regexp.MustCompile(`-\d{5}-of-\d{5}$`)
+	// onnxVariants are the suffixes of the ONNX exports of a model in other precisions, e.g. "model_fp16.onnx".
+	onnxVariants = []string{"quantized", "fp16", "int8", "uint8", "q4", "q4f16", "bnb4", "q8"}
+	// supportFileExtensions are the extensions of the config, tokenizer and code files loading a model needs.
+	supportFileExtensions = []string{".json", ".txt", ".model", ".jinja", ".tiktoken", ".py"}
+)
+
+// DiffusersPipelineFile is the file listing the components of a diffusers pipeline, each stored in its own
+// directory.
+const DiffusersPipelineFile = "model_index.json"
+
+// SelectWeightFiles selects among the files of a model repository the minimal set of files needed to load the
+// model in a format, along with its config and tokenizer files.
+//
+// The variant selects among the weights of the format:
+//   - for safetensors and PyTorch weights, the variant of the files, e.g. "fp16" for "model.fp16.safetensors",
+//     falling back to the files without variant;
+//   - for GGUF files, the quantization, e.g. "Q4_K_M", which may only be empty if the repository has a single
+//     GGUF model. GGUF files are self-contained, so no config or tokenizer file is selected;
+//   - for ONNX files, the precision suffix, e.g. "quantized" for "model_quantized.onnx".
+//
+// Weights at the root of the repository take precedence over weights in directories, which are only selected
+// for repositories made of several models, such as diffusers pipelines. See SelectPipelineWeightFiles to also
+// select the components of a diffusers pipeline without weights.
+func SelectWeightFiles(filenames []string, format WeightFormat, variant string) (*WeightFiles, error) {
+	return SelectPipelineWeightFiles(filenames, nil, format, variant)
+}
+
+// SelectPipelineWeightFiles selects files like SelectWeightFiles in the repository of a diffusers pipeline, also
+// selecting the config and tokenizer files of its components, including components without weights such as its
+// scheduler. The components are the directories listed in DiffusersPipelineFile, see ParsePipelineComponents.
+func SelectPipelineWeightFiles(filenames []string, components []string, format WeightFormat, variant string) (*WeightFiles, error) {
+	var weights []string
+	switch format {
+	case WeightFormatSafetensors, WeightFormatPyTorch:
+		weights = selectTensorFiles(filenames, format, variant)
+	case WeightFormatGGUF:
+		return selectGGUFFiles(filenames, variant)
+	case WeightFormatONNX:
+		weights = selectONNXFiles(filenames, variant)
+	default:
+		return nil, fmt.Errorf("unsupported weight format %q", format)
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no %s weights found for variant %q", format, variant)
+	}
+
+	files := &WeightFiles{Weights: weights}
+	dirs := map[string]bool{".": true}
+	for _, weight := range weights {
+		dirs[path.Dir(weight)] = true
+	}
+	for _, component := range components {
+		dirs[component] = true
+	}
+	for _, filename := range filenames {
+		if dirs[path.Dir(filename)] && isSupportFile(filename) && !slices.Contains(weights, filename) {
+			files.Support = append(files.Support, filename)
+		}
+	}
+	return files, nil
+}
+
+// ParsePipelineComponents returns the directories of the components of a diffusers pipeline, sorted, from the
+// content of its DiffusersPipelineFile. Components declared without a class, such as a disabled safety checker,
+// are left out.
+func ParsePipelineComponents(data []byte) ([]string, error) {
+	var index map[string]json.RawMessage
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", DiffusersPipelineFile, err)
+	}
+	var components []string
+	for name, value := range index {
+		// Components are declared as ["library", "class"], next to attributes such as "_class_name".
+		var class []*string
+		if strings.HasPrefix(name, "_") || json.Unmarshal(value, &class) != nil {
+			continue
+		}
+		if len(class) == 2 && class[0] != nil && class[1] != nil {
+			components = append(components, name)
+		}
+	}
+	slices.Sort(components)
+	return components, nil
+}
+
+// SelectWeightFiles selects among the files of the model the minimal set of files needed to load it in a format.
+// See SelectWeightFiles.
+func (m *Model) SelectWeightFiles(format WeightFormat, variant string) (*WeightFiles, error) {
+	filenames := make([]string, len(m.Siblings))
+	for i, sibling := range m.Siblings {
+		filenames[i] = sibling.Rfilename
+	}
+	return SelectWeightFiles(filenames, format, variant)
+}
+
+// ListWeightFiles lists the files of a model at a revision and selects the minimal set of files needed to load
+// it in a format. The components of diffusers pipelines are read from their DiffusersPipelineFile, see
+// SelectPipelineWeightFiles.
+func (r *Repository) ListWeightFiles(ctx context.Context, id string, revision string, format WeightFormat, variant string) (*WeightFiles, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	files, err := r.listRepoFiles(ctx, RepoTypeModel, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	filenames := make([]string, len(files))
+	for i, file := range files {
+		filenames[i] = file.Path
+	}
+	if !slices.Contains(filenames, DiffusersPipelineFile) {
+		return SelectWeightFiles(filenames, format, variant)
+	}
+
+	var index bytes.Buffer
+	rawURL := r.httpClient.resolveURL(RepoTypeModel, id, revision, DiffusersPipelineFile)
+	if err := r.httpClient.readURL(ctx, rawURL, &index); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", DiffusersPipelineFile, err)
+	}
+	components, err := ParsePipelineComponents(index.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return SelectPipelineWeightFiles(filenames, components, format, variant)
+}
+
+// selectTensorFiles selects safetensors or PyTorch weights, along with their index for sharded weights.
+func selectTensorFiles(filenames []string, format WeightFormat, variant string) []string {
+	ext := ".safetensors"
+	if format == WeightFormatPyTorch {
+		ext = ".bin"
+	}
+	// The weights of each directory are grouped by variant.
+	byDir := make(map[string]map[string][]string)
+	for _, filename := range filenames {
+		dir, base := path.Split(filename)
+		var fileVariant string
+		switch {
+		case strings.HasSuffix(base, ext):
+			stem := shardSuffixRegexp.ReplaceAllString(strings.TrimSuffix(base, ext), "")
+			if !isWeightStem(stem) {
+				continue
+			}
+			if i := strings.LastIndex(stem, "."); i >= 0 {
+				fileVariant = stem[i+1:]
+			}
+		case strings.Contains(base, ext+".index.") && strings.HasSuffix(base, ".json"):
+			// Indexes are named "model.safetensors.index.json" or "model.safetensors.index.fp16.json".
+			_, after, _ := strings.Cut(base, ext+".index.")
+			fileVariant = strings.TrimSuffix(strings.TrimSuffix(after, "json"), ".")
+		default:
+			continue
+		}
+		if byDir[dir] == nil {
+			byDir[dir] = make(map[string][]string)
+		}
+		byDir[dir][fileVariant] = append(byDir[dir][fileVariant], filename)
+	}
+
+	var selected []string
+	for _, dir := range weightDirs(byDir) {
+		files, ok := byDir[dir][variant]
+		if !ok {
+			files = byDir[dir][""]
+		}
+		selected = append(selected, indexedFiles(files, ext)...)
+	}
+	return selected
+}
+
+// indexedFiles keeps only the shards of the index among weights of a directory, if there is an index, as some
+// repositories also store the same weights in a single file, e.g. "consolidated.safetensors".
+func indexedFiles(files []string, ext string) []string {
+	for _, file := range files {
+		prefix, _, ok := strings.Cut(path.Base(file), ext+".index")
+		if !ok {
+			continue
+		}
+		return slices.DeleteFunc(slices.Clone(files), func(f string) bool {
+			base := path.Base(f)
+			return base != path.Base(file) && !strings.HasPrefix(base, prefix+"-") && !strings.HasPrefix(base, prefix+".")
+		})
+	}
+	return files
+}
+
+// isWeightStem reports whether the name of a file, without extension and shard suffix, is the name of model
+// weights rather than of training state such as "training_args.bin".
+func isWeightStem(stem string) bool {
+	name, _, _ := strings.Cut(stem, ".")
+	return !strings.HasPrefix(name, "training_") && !strings.HasPrefix(name, "optimizer") &&
+		!strings.HasPrefix(name, "scheduler") && !strings.HasPrefix(name, "rng_state")
+}
+
+// weightDirs returns the root directory if it holds weights, or every directory holding weights otherwise.
+func weightDirs[T any](byDir map[string]T) []string {
+	if _, ok := byDir[""]; ok {
+		return []string{""}
+	}
+	var dirs []string
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// selectONNXFiles selects ONNX exports of a precision, along with their external data files.
+func selectONNXFiles(filenames []string, variant string) []string {
+	byDir := make(map[string][]string)
+	for _, filename := range filenames {
+		dir, base := path.Split(filename)
+		if !strings.HasSuffix(base, ".onnx") {
+			continue
+		}
+		stem := strings.TrimSuffix(base, ".onnx")
+		fileVariant := ""
+		for _, v := range onnxVariants {
+			if strings.HasSuffix(stem, "_"+v) {
+				fileVariant = v
+			}
+		}
+		if fileVariant == variant {
+			byDir[dir] = append(byDir[dir], filename)
+		}
+	}
+
+	var selected []string
+	for _, dir := range weightDirs(byDir) {
+		for _, model := range byDir[dir] {
+			selected = append(selected, model)
+			// Models over 2GB store their tensors in external files, e.g. "model.onnx_data".
+			for _, filename := range filenames {
+				if filename != model && (strings.HasPrefix(filename, model+"_data") || strings.HasPrefix(filename, model+".data")) {
+					selected = append(selected, filename)
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// selectGGUFFiles selects the GGUF files of a quantization, including every file of split models.
+func selectGGUFFiles(filenames []string, quantType string) (*WeightFiles, error) {
+	byQuant := make(map[string][]string)
+	for _, filename := range filenames {
+		base := path.Base(filename)
+		if !strings.EqualFold(path.Ext(base), ".gguf") {
+			continue
+		}
+		// Multimodal projectors are only needed along with the model, not as a model of their own.
+		if strings.HasPrefix(strings.ToLower(base), "mmproj") {
+			continue
+		}
+		quant := ggufQuantType(base)
+		byQuant[quant] = append(byQuant[quant], filename)
+	}
+	if quantType == "" && len(byQuant) == 1 {
+		for _, files := range byQuant {
+			return &WeightFiles{Weights: files}, nil
+		}
+	}
+	files, ok := byQuant[strings.ToUpper(quantType)]
+	if !ok || quantType == "" {
+		var available []string
+		for quant := range byQuant {
+			available = append(available, quant)
+		}
+		slices.Sort(available)
+		return nil, fmt.Errorf("no GGUF weights found for quantization %q, available: %s", quantType, strings.Join(available, ", "))
+	}
+	return &WeightFiles{Weights: files}, nil
+}
+
+// ggufQuantType returns the quantization in the name of a GGUF file, e.g. "Q4_K_M" for "model-Q4_K_M.gguf",
+// or an empty string if the name does not contain any known quantization.
+func ggufQuantType(base string) string {
+	stem := strings.ToUpper(shardSuffixRegexp.ReplaceAllString(strings.TrimSuffix(base, path.Ext(base)), ""))
+	tokens := strings.FieldsFunc(stem, func(r rune) bool {
+		return r == '-' || r == '.'
+	})
+	// Quantizations contain underscores, so the name is split on other separators and the longest known
+	// quantization ending the name wins, e.g. "Q4_K_M" over "Q4_K".
+	best := ""
+	for _, token := range tokens {
+		for _, name := range ggufFileTypeNames {
+			if (token == name || strings.HasSuffix(token, "_"+name)) && len(name) > len(best) {
+				best = name
+			}
+		}
+	}
+	return best
+}
+
+func isSupportFile(filename string) bool {
+	base := path.Base(filename)
+	if strings.Contains(base, ".index.") || strings.HasPrefix(base, "training_") {
+		return false
+	}
+	return slices.Contains(supportFileExtensions, path.Ext(base))
+}
+
+// WeightFiles are the files needed to load a model in a format.
+type WeightFiles struct {
+	Weights []string
+	// Support holds the config, tokenizer and code files next to the weights, at the root of the repository and in
+	// the directories of pipeline components.
+	Support []string
+}
+
+// Files returns every selected file.
+func (f *WeightFiles) Files() []string {
+	return append(slices.Clone(f.Weights), f.Support...)
+}
+
+// AllowPatterns returns patterns matching exactly the selected files, to be used as SnapshotOptions.AllowPatterns.
+func (f *WeightFiles) AllowPatterns() []string {
+	files := f.Files()
+	for i, file := range files {
+		// Special characters are matched literally by enclosing them in a set.
+		files[i] = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]").Replace(file)
+	}
+	return files
+}
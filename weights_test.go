@@ -0,0 +1,203 @@
+package huggo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectWeightFiles(t *testing.T) {
+	llama := []string{
+		".gitattributes",
+		"README.md",
+		"config.json",
+		"generation_config.json",
+		"tokenizer.json",
+		"tokenizer_config.json",
+		"special_tokens_map.json",
+		"consolidated.safetensors",
+		"model-00001-of-00002.safetensors",
+		"model-00002-of-00002.safetensors",
+		"model.safetensors.index.json",
+		"pytorch_model.bin",
+		"training_args.bin",
+		"onnx/model.onnx",
+		"onnx/model.onnx_data",
+		"onnx/model_quantized.onnx",
+		"onnx/model_q4f16.onnx",
+		"original/params.json",
+	}
+	diffusers := []string{
+		"model_index.json",
+		"unet/config.json",
+		"unet/diffusion_pytorch_model.safetensors",
+		"unet/diffusion_pytorch_model.fp16.safetensors",
+		"vae/config.json",
+		"vae/diffusion_pytorch_model.safetensors",
+		"text_encoder/config.json",
+		"text_encoder/model.fp16-00001-of-00002.safetensors",
+		"text_encoder/model.fp16-00002-of-00002.safetensors",
+		"text_encoder/model.safetensors.index.fp16.json",
+		"tokenizer/merges.txt",
+		"tokenizer/special_tokens_map.json",
+		"tokenizer/tokenizer_config.json",
+		"tokenizer/vocab.json",
+		"scheduler/scheduler_config.json",
+		"examples/prompts.txt",
+		"assets/metadata.json",
+	}
+	components := []string{"scheduler", "text_encoder", "tokenizer", "unet", "vae"}
+	gguf := []string{
+		"README.md",
+		"Llama-3-8B-Instruct-Q4_K_M.gguf",
+		"Llama-3-8B-Instruct-Q4_K_S.gguf",
+		"Llama-3-8B-Instruct-Q8_0-00001-of-00002.gguf",
+		"Llama-3-8B-Instruct-Q8_0-00002-of-00002.gguf",
+		"mmproj-model-f16.gguf",
+	}
+	support := []string{"config.json", "generation_config.json", "tokenizer.json", "tokenizer_config.json", "special_tokens_map.json"}
+
+	tests := []struct {
+		name       string
+		filenames  []string
+		components []string
+		format     WeightFormat
+		variant    string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:      "sharded safetensors",
+			filenames: llama,
+			format:    WeightFormatSafetensors,
+			want:      append([]string{"model-00001-of-00002.safetensors", "model-00002-of-00002.safetensors", "model.safetensors.index.json"}, support...),
+		},
+		{
+			name:      "pytorch",
+			filenames: llama,
+			format:    WeightFormatPyTorch,
+			want:      append([]string{"pytorch_model.bin"}, support...),
+		},
+		{
+			name:      "onnx",
+			filenames: llama,
+			format:    WeightFormatONNX,
+			want:      append([]string{"onnx/model.onnx", "onnx/model.onnx_data"}, support...),
+		},
+		{
+			name:      "onnx variant",
+			filenames: llama,
+			format:    WeightFormatONNX,
+			variant:   "quantized",
+			want:      append([]string{"onnx/model_quantized.onnx"}, support...),
+		},
+		{
+			name:       "diffusers variant",
+			filenames:  diffusers,
+			components: components,
+			format:     WeightFormatSafetensors,
+			variant:    "fp16",
+			want: []string{
+				"text_encoder/model.fp16-00001-of-00002.safetensors",
+				"text_encoder/model.fp16-00002-of-00002.safetensors",
+				"text_encoder/model.safetensors.index.fp16.json",
+				"unet/diffusion_pytorch_model.fp16.safetensors",
+				"vae/diffusion_pytorch_model.safetensors",
+				"model_index.json",
+				"unet/config.json",
+				"vae/config.json",
+				"text_encoder/config.json",
+				"tokenizer/merges.txt",
+				"tokenizer/special_tokens_map.json",
+				"tokenizer/tokenizer_config.json",
+				"tokenizer/vocab.json",
+				"scheduler/scheduler_config.json",
+			},
+		},
+		{
+			name:      "diffusers without components",
+			filenames: diffusers,
+			format:    WeightFormatSafetensors,
+			want: []string{
+				"unet/diffusion_pytorch_model.safetensors",
+				"vae/diffusion_pytorch_model.safetensors",
+				"model_index.json",
+				"unet/config.json",
+				"vae/config.json",
+			},
+		},
+		{
+			name:      "gguf quantization",
+			filenames: gguf,
+			format:    WeightFormatGGUF,
+			variant:   "q8_0",
+			want:      []string{"Llama-3-8B-Instruct-Q8_0-00001-of-00002.gguf", "Llama-3-8B-Instruct-Q8_0-00002-of-00002.gguf"},
+		},
+		{
+			name:      "gguf ambiguous",
+			filenames: gguf,
+			format:    WeightFormatGGUF,
+			wantErr:   true,
+		},
+		{
+			name:      "missing format",
+			filenames: gguf,
+			format:    WeightFormatSafetensors,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectPipelineWeightFiles(tt.filenames, tt.components, tt.format, tt.variant)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(got.Files(), tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got.Files())
+			}
+		})
+	}
+}
+
+func TestParsePipelineComponents(t *testing.T) {
+	data := `{
+		"_class_name": "StableDiffusionPipeline",
+		"_diffusers_version": "0.21.0",
+		"requires_safety_checker": false,
+		"safety_checker": [null, null],
+		"scheduler": ["diffusers", "PNDMScheduler"],
+		"text_encoder": ["transformers", "CLIPTextModel"],
+		"tokenizer": ["transformers", "CLIPTokenizer"],
+		"unet": ["diffusers", "UNet2DConditionModel"],
+		"vae": ["diffusers", "AutoencoderKL"]
+	}`
+	got, err := ParsePipelineComponents([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"scheduler", "text_encoder", "tokenizer", "unet", "vae"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, err := ParsePipelineComponents([]byte(`[]`)); err == nil {
+		t.Errorf("Expected error for invalid pipeline file")
+	}
+}
+
+func TestWeightFiles_AllowPatterns(t *testing.T) {
+	files := &WeightFiles{Weights: []string{"model[1].safetensors"}, Support: []string{"config.json"}}
+	patterns := files.AllowPatterns()
+	for _, file := range files.Files() {
+		if !matchAnyFilePattern(file, patterns) {
+			t.Errorf("Expected %s to match %v", file, patterns)
+		}
+	}
+	if matchAnyFilePattern("model1.safetensors", patterns) {
+		t.Errorf("Expected patterns to match files literally")
+	}
+}